@@ -0,0 +1,212 @@
+package metrc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// AdditiveTemplate represents a reusable additive recipe in Metrc, e.g. a nutrient mix or pesticide.
+// `AdditiveTemplate.Id` is required when updating a template.
+// See: https://testing-api-ca.metrc.com/Documentation#AdditivesTemplates.get_additivestemplates_v1_{id}.GET
+type AdditiveTemplate struct {
+	Id                                          int                       `json:"Id,omitempty"`
+	Name                                        string                    `json:"Name"`
+	AdditiveType                                string                    `json:"AdditiveType"`
+	ApplicationDevice                           string                    `json:"ApplicationDevice"`
+	EpaRegistrationNumber                       *int                      `json:"EpaRegistrationNumber"`
+	ProductTradeName                            string                    `json:"ProductTradeName"`
+	ProductSupplier                             string                    `json:"ProductSupplier"`
+	RestrictiveEntryIntervalQuantityDescription *string                   `json:"RestrictiveEntryIntervalQuantityDescription"`
+	RestrictiveEntryIntervalTimeDescription     *string                   `json:"RestrictiveEntryIntervalTimeDescription"`
+	Note                                        *string                   `json:"Note"`
+	ActiveIngredients                           []PlantAdditiveIngredient `json:"ActiveIngredients"`
+	IsActive                                    bool                      `json:"IsActive,omitempty"`
+	LastModified                                string                    `json:"LastModified,omitempty"`
+}
+
+// GetAdditiveTemplatesById gets the Additive Template with the specified ID.
+// See: https://testing-api-ca.metrc.com/Documentation#AdditivesTemplates.get_additivestemplates_v1_{id}.GET
+func (m *Metrc) GetAdditiveTemplatesById(id int, licenseNumber string) (AdditiveTemplate, error) {
+	endpoint := fmt.Sprintf("additivestemplates/v1/%d", id)
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	var at AdditiveTemplate
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return at, fmt.Errorf("could not get additive template by id response: %s", err)
+	}
+
+	err = json.Unmarshal(responseBody, &at)
+	if err != nil {
+		return at, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return at, nil
+}
+
+// helper method to get additive templates with the "status" endpoint
+func (m *Metrc) getAdditiveTemplatesByStatus(status string, licenseNumber string) ([]AdditiveTemplate, error) {
+	endpoint := fmt.Sprintf("additivestemplates/v1/%s", status)
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	var at []AdditiveTemplate
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return at, fmt.Errorf("could not get additive templates by status response: %s", err)
+	}
+
+	err = json.Unmarshal(responseBody, &at)
+	if err != nil {
+		return at, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return at, nil
+}
+
+// GetAdditiveTemplatesActive gets all active Additive Templates.
+// See: https://testing-api-ca.metrc.com/Documentation#AdditivesTemplates.get_additivestemplates_v1_active.GET
+func (m *Metrc) GetAdditiveTemplatesActive(licenseNumber string) ([]AdditiveTemplate, error) {
+	templates, err := m.getAdditiveTemplatesByStatus("active", licenseNumber)
+	if err != nil {
+		return []AdditiveTemplate{}, fmt.Errorf("could not get active additive templates: %s", err)
+	}
+
+	return templates, nil
+}
+
+// GetAdditiveTemplatesInactive gets all inactive Additive Templates.
+// See: https://testing-api-ca.metrc.com/Documentation#AdditivesTemplates.get_additivestemplates_v1_inactive.GET
+func (m *Metrc) GetAdditiveTemplatesInactive(licenseNumber string) ([]AdditiveTemplate, error) {
+	templates, err := m.getAdditiveTemplatesByStatus("inactive", licenseNumber)
+	if err != nil {
+		return []AdditiveTemplate{}, fmt.Errorf("could not get inactive additive templates: %s", err)
+	}
+
+	return templates, nil
+}
+
+// PostAdditiveTemplatesCreate creates new Additive Templates.
+// See: https://testing-api-ca.metrc.com/Documentation#AdditivesTemplates.post_additivestemplates_v1.POST
+func (m *Metrc) PostAdditiveTemplatesCreate(templates []AdditiveTemplate, licenseNumber string) ([]byte, error) {
+	endpoint := "additivestemplates/v1"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(templates)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal additive templates: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed posting additive templates: %s", err)
+	}
+
+	return resp, nil
+}
+
+// PutAdditiveTemplatesUpdate updates existing Additive Templates. Note that for this endpoint, `AdditiveTemplate.Id` is required in each `AdditiveTemplate` in the input slice.
+// See: https://testing-api-ca.metrc.com/Documentation#AdditivesTemplates.put_additivestemplates_v1.PUT
+func (m *Metrc) PutAdditiveTemplatesUpdate(templates []AdditiveTemplate, licenseNumber string) ([]byte, error) {
+	endpoint := "additivestemplates/v1"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(templates)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal additive templates: %s", err)
+	}
+
+	resp, err := m.Client.Put(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed putting additive templates: %s", err)
+	}
+
+	return resp, nil
+}
+
+// DeleteAdditiveTemplateById archives an existing Additive Template by ID.
+// See: https://testing-api-ca.metrc.com/Documentation#AdditivesTemplates.delete_additivestemplates_v1_{id}.DELETE
+func (m *Metrc) DeleteAdditiveTemplateById(id int, licenseNumber string) ([]byte, error) {
+	endpoint := fmt.Sprintf("additivestemplates/v1/%d", id)
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	resp, err := m.Client.Delete(endpoint)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed deleting additive template %d: %s", id, err)
+	}
+
+	return resp, nil
+}
+
+// PlantAdditive expands the template into a `PlantAdditivePost` applying totalAmountApplied across plantLabels.
+func (at AdditiveTemplate) PlantAdditive(plantLabels []string, totalAmountApplied float64, actualDate string) PlantAdditivePost {
+	ingredients := make([]PlantAdditiveIngredient, len(at.ActiveIngredients))
+	copy(ingredients, at.ActiveIngredients)
+
+	labels := make([]string, len(plantLabels))
+	copy(labels, plantLabels)
+
+	return PlantAdditivePost{
+		AdditiveType:          at.AdditiveType,
+		ProductTradeName:      at.ProductTradeName,
+		EpaRegistrationNumber: at.EpaRegistrationNumber,
+		ProductSupplier:       at.ProductSupplier,
+		ApplicationDevice:     at.ApplicationDevice,
+		TotalAmountApplied:    strconv.FormatFloat(totalAmountApplied, 'f', -1, 64),
+		ActiveIngredients:     ingredients,
+		PlantLabels:           labels,
+		ActualDate:            actualDate,
+	}
+}
+
+// PlantBatchAdditives expands the template into one `PlantBatchAdditive` per plant batch name.
+// The same totalAmountApplied is recorded against each batch.
+func (at AdditiveTemplate) PlantBatchAdditives(plantBatchNames []string, totalAmountApplied float64, unitOfMeasure string, actualDate string) []PlantBatchAdditive {
+	additives := make([]PlantBatchAdditive, 0, len(plantBatchNames))
+	for _, name := range plantBatchNames {
+		ingredients := make([]PlantBatchAdditiveActiveIngredient, 0, len(at.ActiveIngredients))
+		for _, ai := range at.ActiveIngredients {
+			ingredients = append(ingredients, PlantBatchAdditiveActiveIngredient{
+				Name:       ai.Name,
+				Percentage: ai.Percentage,
+			})
+		}
+
+		additives = append(additives, PlantBatchAdditive{
+			AdditiveType:             at.AdditiveType,
+			ProductTradeName:         at.ProductTradeName,
+			EpaRegistrationNumber:    at.EpaRegistrationNumber,
+			ProductSupplier:          at.ProductSupplier,
+			ApplicationDevice:        at.ApplicationDevice,
+			TotalAmountApplied:       totalAmountApplied,
+			TotalAmountUnitOfMeasure: unitOfMeasure,
+			ActiveIngredients:        ingredients,
+			PlantBatchName:           name,
+			ActualDate:               actualDate,
+		})
+	}
+
+	return additives
+}
+
+// PostPlantsAdditivesFromTemplate fetches the Additive Template with templateId and applies it to the Plants with plantLabels.
+func (m *Metrc) PostPlantsAdditivesFromTemplate(templateId int, plantLabels []string, totalAmountApplied float64, actualDate string, licenseNumber string) ([]byte, error) {
+	at, err := m.GetAdditiveTemplatesById(templateId, licenseNumber)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not get additive template %d: %s", templateId, err)
+	}
+
+	additives := []PlantAdditivePost{at.PlantAdditive(plantLabels, totalAmountApplied, actualDate)}
+	return m.PostPlantsAdditives(additives, licenseNumber)
+}
+
+// PostPlantBatchesAdditivesFromTemplate fetches the Additive Template with templateId and applies it to the plant batches with plantBatchNames.
+func (m *Metrc) PostPlantBatchesAdditivesFromTemplate(templateId int, plantBatchNames []string, totalAmountApplied float64, unitOfMeasure string, actualDate string, licenseNumber string) ([]byte, error) {
+	at, err := m.GetAdditiveTemplatesById(templateId, licenseNumber)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not get additive template %d: %s", templateId, err)
+	}
+
+	additives := at.PlantBatchAdditives(plantBatchNames, totalAmountApplied, unitOfMeasure, actualDate)
+	return m.PostPlantBatchesAdditives(additives, licenseNumber)
+}
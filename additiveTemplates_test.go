@@ -0,0 +1,50 @@
+package metrc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdditiveTemplatesActive_Integration(t *testing.T) {
+	_, err := m.GetAdditiveTemplatesActive(licenseNumber)
+	assert.NoError(t, err)
+}
+
+func TestAdditiveTemplatesInactive_Integration(t *testing.T) {
+	_, err := m.GetAdditiveTemplatesInactive(licenseNumber)
+	assert.NoError(t, err)
+}
+
+func TestAdditiveTemplatesCreateUpdateDelete_Integration(t *testing.T) {
+	// TODO: Implement.
+}
+
+func TestAdditiveTemplatesExpand(t *testing.T) {
+	epa := 12345
+	at := AdditiveTemplate{
+		Name:                  "Weekly Feed",
+		AdditiveType:          "Fertilizer",
+		ApplicationDevice:     "Sprayer",
+		EpaRegistrationNumber: &epa,
+		ProductTradeName:      "Grow Big",
+		ProductSupplier:       "FoxFarm",
+		ActiveIngredients: []PlantAdditiveIngredient{
+			{Name: "Nitrogen", Percentage: 6.0},
+			{Name: "Phosphate", Percentage: 4.0},
+		},
+	}
+
+	pa := at.PlantAdditive([]string{"1A4FF0000000022000000001", "1A4FF0000000022000000002"}, 2.5, "2021-06-01")
+	assert.Equal(t, "Fertilizer", pa.AdditiveType)
+	assert.Equal(t, "2.5", pa.TotalAmountApplied)
+	assert.Equal(t, &epa, pa.EpaRegistrationNumber)
+	assert.Equal(t, at.ActiveIngredients, pa.ActiveIngredients)
+	assert.Equal(t, 2, len(pa.PlantLabels))
+
+	pbas := at.PlantBatchAdditives([]string{"Batch A", "Batch B"}, 1.0, "Gallons", "2021-06-01")
+	assert.Equal(t, 2, len(pbas))
+	assert.Equal(t, "Batch B", pbas[1].PlantBatchName)
+	assert.Equal(t, "Gallons", pbas[1].TotalAmountUnitOfMeasure)
+	assert.Equal(t, []PlantBatchAdditiveActiveIngredient{{Name: "Nitrogen", Percentage: 6.0}, {Name: "Phosphate", Percentage: 4.0}}, pbas[0].ActiveIngredients)
+}
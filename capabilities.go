@@ -0,0 +1,138 @@
+package metrc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrNotPermitted is returned, wrapped with the license and missing capability, when a write is rejected by the `CapabilityGuard`.
+// Check for it with `errors.Is`.
+var ErrNotPermitted = errors.New("not permitted")
+
+// CapabilityGuard checks writes against the capabilities of the facility they are made for, before a request is sent to Metrc.
+// Capabilities are loaded with `Metrc.Facilities` the first time a license is checked and kept until `Reset`.
+// Set `Metrc.Capabilities` to a guard to enable it; share one guard between every `Metrc` using the same keys.
+type CapabilityGuard struct {
+	mu         sync.Mutex
+	facilities map[string]FacilitiesFacilityType
+}
+
+// MakeCapabilityGuard creates an empty guard.
+func MakeCapabilityGuard() *CapabilityGuard {
+	return &CapabilityGuard{}
+}
+
+// Reset forgets the loaded capabilities, so they are loaded again on the next check.
+func (g *CapabilityGuard) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.facilities = nil
+}
+
+// facilityType returns the capabilities of a license, loading every facility the first time it is needed
+// and again if the license was not among them.
+func (g *CapabilityGuard) facilityType(m *Metrc, licenseNumber string) (FacilitiesFacilityType, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ft, ok := g.facilities[licenseNumber]
+	if ok {
+		return ft, nil
+	}
+
+	facilities, err := m.Facilities()
+	if err != nil {
+		return FacilitiesFacilityType{}, fmt.Errorf("could not load facility capabilities: %s", err)
+	}
+
+	g.facilities = map[string]FacilitiesFacilityType{}
+	for _, f := range facilities {
+		g.facilities[f.License.Number] = f.FacilityType
+	}
+
+	ft, ok = g.facilities[licenseNumber]
+	if !ok {
+		return FacilitiesFacilityType{}, fmt.Errorf("license %s is not among the facilities for these keys", licenseNumber)
+	}
+	return ft, nil
+}
+
+// Allows reports whether the facility for a license has every capability, named after the fields of `FacilitiesFacilityType`.
+func (g *CapabilityGuard) Allows(m *Metrc, licenseNumber string, capabilities ...string) (bool, error) {
+	ft, err := g.facilityType(m, licenseNumber)
+	if err != nil {
+		return false, err
+	}
+
+	missing, err := missingCapabilities(ft, capabilities)
+	if err != nil {
+		return false, err
+	}
+	return len(missing) == 0, nil
+}
+
+// missingCapabilities returns the capabilities that the facility type does not have.
+func missingCapabilities(ft FacilitiesFacilityType, capabilities []string) ([]string, error) {
+	v := reflect.ValueOf(ft)
+
+	var missing []string
+	for _, c := range capabilities {
+		f := v.FieldByName(c)
+		if !f.IsValid() || f.Kind() != reflect.Bool {
+			return nil, fmt.Errorf("unknown facility capability %s", c)
+		}
+		if !f.Bool() {
+			missing = append(missing, c)
+		}
+	}
+	return missing, nil
+}
+
+// checkCapabilities rejects an operation the facility for a license is not permitted to make, when `Metrc.Capabilities` is set.
+func (m *Metrc) checkCapabilities(licenseNumber string, operation string, capabilities ...string) error {
+	if m.Capabilities == nil {
+		return nil
+	}
+
+	ft, err := m.Capabilities.facilityType(m, licenseNumber)
+	if err != nil {
+		return err
+	}
+
+	missing, err := missingCapabilities(ft, capabilities)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: license %s cannot %s, the facility does not have %s", ErrNotPermitted, licenseNumber, operation, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// salesCustomerCapabilities are the capabilities required to sell to each customer type.
+var salesCustomerCapabilities = map[string]string{
+	"Consumer":        "CanSellToConsumers",
+	"Patient":         "CanSellToPatients",
+	"Caregiver":       "CanSellToCaregivers",
+	"ExternalPatient": "CanSellToExternalPatients",
+}
+
+// checkSalesCapabilities rejects receipts for customer types the facility cannot sell to.
+func (m *Metrc) checkSalesCapabilities(receipts []SalesReceiptPost, licenseNumber string) error {
+	for _, r := range receipts {
+		c, ok := salesCustomerCapabilities[r.SalesCustomerType]
+		if !ok {
+			continue
+		}
+
+		err := m.checkCapabilities(licenseNumber, fmt.Sprintf("sell to %s customers", r.SalesCustomerType), c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package metrc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilityGuard(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"facilities/v1": `[
+			{"License": {"Number": "RETAIL-1"}, "FacilityType": {"IsRetail": true, "CanSellToConsumers": true}},
+			{"License": {"Number": "GROW-1"}, "FacilityType": {"CanGrowPlants": true}}
+		]`,
+	})
+	m := &Metrc{Client: fc}
+
+	// The guard is opt-in.
+	_, err := m.PostPlantBatchesCreatePlantings([]PlantBatchPlanting{}, "RETAIL-1")
+	assert.NoError(t, err)
+	assert.Empty(t, fc.gets)
+	fc.bodies = map[string][]byte{}
+
+	m.Capabilities = MakeCapabilityGuard()
+
+	_, err = m.PostPlantBatchesCreatePlantings([]PlantBatchPlanting{}, "RETAIL-1")
+	assert.True(t, errors.Is(err, ErrNotPermitted))
+	assert.Contains(t, err.Error(), "CanGrowPlants")
+	assert.Empty(t, fc.bodies)
+
+	_, err = m.PostPlantBatchesCreatePlantings([]PlantBatchPlanting{}, "GROW-1")
+	assert.NoError(t, err)
+
+	_, err = m.PostSalesReceipts([]SalesReceiptPost{{SalesCustomerType: "Consumer"}}, "RETAIL-1")
+	assert.NoError(t, err)
+	_, err = m.PostSalesReceipts([]SalesReceiptPost{{SalesCustomerType: "Patient"}}, "RETAIL-1")
+	assert.True(t, errors.Is(err, ErrNotPermitted))
+	assert.Contains(t, err.Error(), "CanSellToPatients")
+
+	_, err = m.PutPackagesDonationFlag([]PackageFlag{}, "GROW-1")
+	assert.True(t, errors.Is(err, ErrNotPermitted))
+
+	// Capabilities are loaded once.
+	assert.Len(t, fc.gets, 1)
+
+	_, err = m.PostPlantsWaste([]PlantWaste{}, "UNKNOWN-1")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrNotPermitted))
+
+	ok, err := m.Capabilities.Allows(m, "GROW-1", "CanGrowPlants", "CanSellToConsumers")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	_, err = m.Capabilities.Allows(m, "GROW-1", "CanFly")
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package metrc
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 )
@@ -20,6 +21,12 @@ type ClientInterface interface {
 	Put(endpoint string, body []byte) ([]byte, error)
 }
 
+// StreamClientInterface is implemented by clients that can return a response body before it has been read.
+// The list iterators decode from the stream when the client implements it, and read the whole response with `Get` otherwise.
+type StreamClientInterface interface {
+	GetStream(endpoint string) (io.ReadCloser, error)
+}
+
 // HttpClient is a convenience client for raw HTTP calls.
 // Wraps a generic `http.Client` and implements `ClientInterface`.
 type HttpClient struct {
@@ -48,6 +55,16 @@ func (c *HttpClient) Get(endpoint string) ([]byte, error) {
 	return c.do(req)
 }
 
+// GetStream executes `GET` requests to the specified endpoint and returns the unread response body, which the caller must close.
+func (c *HttpClient) GetStream(endpoint string) (io.ReadCloser, error) {
+	endpointUrl := fmt.Sprintf("%s/%s", metrcUrl, endpoint)
+	req, err := http.NewRequest("GET", endpointUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not make new request: %s", err)
+	}
+	return c.stream(req)
+}
+
 // Post executes `POST` requests to the specified endpoint and body.
 func (c *HttpClient) Post(endpoint string, body []byte) ([]byte, error) {
 	endpointUrl := fmt.Sprintf("%s/%s", metrcUrl, endpoint)
@@ -83,22 +100,36 @@ func (c *HttpClient) Put(endpoint string, body []byte) ([]byte, error) {
 
 // do is a boilerplate funtion that executes a request and returns a response.
 func (c *HttpClient) do(req *http.Request) ([]byte, error) {
-	req.SetBasicAuth(c.VendorKey, c.UserKey)
-
-	resp, err := c.Client.Do(req)
+	respBody, err := c.stream(req)
 	if err != nil {
-		return []byte{}, fmt.Errorf("could not do request: %s", err)
+		return []byte{}, err
 	}
-	defer resp.Body.Close()
+	defer respBody.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := ioutil.ReadAll(respBody)
 	if err != nil {
 		return []byte{}, fmt.Errorf("could not read from response: %s", err)
 	}
+	return body, nil
+}
+
+// stream executes a request and returns the body of a successful response without reading it.
+func (c *HttpClient) stream(req *http.Request) (io.ReadCloser, error) {
+	req.SetBasicAuth(c.VendorKey, c.UserKey)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not do request: %s", err)
+	}
 
 	respCode := resp.StatusCode
 	if respCode != 200 {
-		return []byte{}, fmt.Errorf("response failed with code %d and body %s", respCode, string(body))
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not read from response: %s", err)
+		}
+		return nil, fmt.Errorf("response failed with code %d and body %s", respCode, string(body))
 	}
-	return body, nil
+	return resp.Body, nil
 }
@@ -1,6 +1,9 @@
 package metrc
 
 import (
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,3 +21,57 @@ func TestHttpClientGet(t *testing.T) {
 }
 
 // TODO: Add tests for other client functions.
+
+// fakeClient implements ClientInterface without calling Metrc.
+// Responses are keyed by the full endpoint, falling back to the endpoint path without the query string,
+// and requests with a body are recorded.
+type fakeClient struct {
+	mu        sync.Mutex
+	responses map[string]string
+	gets      []string
+	bodies    map[string][]byte
+}
+
+func makeFakeClient(responses map[string]string) *fakeClient {
+	return &fakeClient{
+		responses: responses,
+		bodies:    map[string][]byte{},
+	}
+}
+
+func (c *fakeClient) respond(endpoint string) ([]byte, error) {
+	if resp, ok := c.responses[endpoint]; ok {
+		return []byte(resp), nil
+	}
+	path := strings.SplitN(endpoint, "?", 2)[0]
+	resp, ok := c.responses[path]
+	if !ok {
+		return []byte{}, fmt.Errorf("no fake response for %s", path)
+	}
+	return []byte(resp), nil
+}
+
+func (c *fakeClient) Get(endpoint string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gets = append(c.gets, endpoint)
+	return c.respond(endpoint)
+}
+
+func (c *fakeClient) Post(endpoint string, body []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bodies[endpoint] = body
+	return []byte{}, nil
+}
+
+func (c *fakeClient) Delete(endpoint string) ([]byte, error) {
+	return []byte{}, nil
+}
+
+func (c *fakeClient) Put(endpoint string, body []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bodies[endpoint] = body
+	return []byte{}, nil
+}
@@ -0,0 +1,569 @@
+package metrc
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GenealogyKind is the kind of object in a `Genealogy`.
+type GenealogyKind string
+
+// The kinds of objects in a `Genealogy`.
+const (
+	GenealogyPlantBatch GenealogyKind = "PlantBatch"
+	GenealogyPlant      GenealogyKind = "Plant"
+	GenealogyHarvest    GenealogyKind = "Harvest"
+	GenealogyPackage    GenealogyKind = "Package"
+	GenealogySale       GenealogyKind = "Sale"
+)
+
+// GenealogyNode is a plant batch, plant, harvest, package, or sale in a `Genealogy`, identified by its kind and name:
+// the name of a plant batch or harvest, the tag label of a plant or package, or the Id of a sales receipt.
+type GenealogyNode struct {
+	Kind GenealogyKind `json:"Kind"`
+	Name string        `json:"Name"`
+}
+
+// PlantBatchNode identifies a plant batch by its name.
+func PlantBatchNode(name string) GenealogyNode {
+	return GenealogyNode{Kind: GenealogyPlantBatch, Name: name}
+}
+
+// PlantNode identifies a Plant by its tag label.
+func PlantNode(label string) GenealogyNode {
+	return GenealogyNode{Kind: GenealogyPlant, Name: label}
+}
+
+// HarvestNode identifies a harvest by its name.
+func HarvestNode(name string) GenealogyNode {
+	return GenealogyNode{Kind: GenealogyHarvest, Name: name}
+}
+
+// PackageNode identifies a Package by its tag label.
+func PackageNode(label string) GenealogyNode {
+	return GenealogyNode{Kind: GenealogyPackage, Name: label}
+}
+
+// SaleNode identifies a sales receipt by its Id.
+func SaleNode(id string) GenealogyNode {
+	return GenealogyNode{Kind: GenealogySale, Name: id}
+}
+
+func (n GenealogyNode) String() string {
+	return fmt.Sprintf("%s %s", n.Kind, n.Name)
+}
+
+// GenealogyEdge points from a source to what was made from it, e.g. from a harvest to a package, or from a package to a sale.
+type GenealogyEdge struct {
+	From GenealogyNode `json:"From"`
+	To   GenealogyNode `json:"To"`
+}
+
+// Genealogy is a directed graph of where plants, harvests, and packages went, built up from Metrc or a local snapshot.
+// Edges point downstream, from sources to the packages and sales derived from them.
+type Genealogy struct {
+	nodes    map[GenealogyNode]bool
+	children map[GenealogyNode]map[GenealogyNode]bool
+	parents  map[GenealogyNode]map[GenealogyNode]bool
+}
+
+// MakeGenealogy creates an empty Genealogy.
+func MakeGenealogy() *Genealogy {
+	return &Genealogy{
+		nodes:    map[GenealogyNode]bool{},
+		children: map[GenealogyNode]map[GenealogyNode]bool{},
+		parents:  map[GenealogyNode]map[GenealogyNode]bool{},
+	}
+}
+
+// AddNode adds a node without edges. Adding a node twice has no effect.
+func (g *Genealogy) AddNode(n GenealogyNode) {
+	g.nodes[n] = true
+}
+
+// AddEdge records that to was made from from, adding both nodes.
+func (g *Genealogy) AddEdge(from GenealogyNode, to GenealogyNode) {
+	g.AddNode(from)
+	g.AddNode(to)
+	if g.children[from] == nil {
+		g.children[from] = map[GenealogyNode]bool{}
+	}
+	if g.parents[to] == nil {
+		g.parents[to] = map[GenealogyNode]bool{}
+	}
+	g.children[from][to] = true
+	g.parents[to][from] = true
+}
+
+// AddPackage adds a Package and the harvests in its `SourceHarvestNames`.
+func (g *Genealogy) AddPackage(p PackageGet) {
+	g.AddNode(PackageNode(p.Label))
+	for _, h := range p.SourceHarvestNameList() {
+		g.AddEdge(HarvestNode(h), PackageNode(p.Label))
+	}
+}
+
+// AddPackagePosts adds Packages created from other Packages, from the `Ingredient` of each `PackagePost`.
+func (g *Genealogy) AddPackagePosts(packages []PackagePost) {
+	for _, p := range packages {
+		g.AddNode(PackageNode(p.Tag))
+		for _, i := range p.Ingredients {
+			g.AddEdge(PackageNode(i.Package), PackageNode(p.Tag))
+		}
+	}
+}
+
+// AddHarvestPackagePosts adds Packages created from harvests. Ingredients given only by `HarvestId` are skipped.
+func (g *Genealogy) AddHarvestPackagePosts(packages []HarvestPackagePost) {
+	for _, p := range packages {
+		g.AddNode(PackageNode(p.Tag))
+		for _, i := range p.Ingredients {
+			if i.HarvestName != nil {
+				g.AddEdge(HarvestNode(*i.HarvestName), PackageNode(p.Tag))
+			}
+		}
+	}
+}
+
+// AddPlant adds a Plant and the plant batch it came from.
+func (g *Genealogy) AddPlant(p Plant) {
+	g.AddNode(PlantNode(p.Label))
+	if p.PlantBatchName != "" {
+		g.AddEdge(PlantBatchNode(p.PlantBatchName), PlantNode(p.Label))
+	}
+}
+
+// AddPlantHarvests adds the Plants harvested into each harvest.
+func (g *Genealogy) AddPlantHarvests(plants []PlantHarvest) {
+	for _, p := range plants {
+		g.AddEdge(PlantNode(p.Plant), HarvestNode(p.HarvestName))
+	}
+}
+
+// AddTransferPackage adds a transferred Package and its `SourcePackageLabels` and `SourceHarvestNames`.
+func (g *Genealogy) AddTransferPackage(p TransferDeliveryPackage) {
+	g.AddNode(PackageNode(p.PackageLabel))
+	for _, l := range splitNames(p.SourcePackageLabels) {
+		g.AddEdge(PackageNode(l), PackageNode(p.PackageLabel))
+	}
+	for _, h := range splitNames(p.SourceHarvestNames) {
+		g.AddEdge(HarvestNode(h), PackageNode(p.PackageLabel))
+	}
+}
+
+// AddSalesReceipt adds the Packages sold on a receipt from Metrc.
+func (g *Genealogy) AddSalesReceipt(r SalesReceiptGet) {
+	sale := SaleNode(fmt.Sprint(r.Id))
+	g.AddNode(sale)
+	for _, t := range r.Transactions {
+		g.AddEdge(PackageNode(t.PackageLabel), sale)
+	}
+}
+
+// AddSalesReceiptPost adds the Packages sold on a receipt from a local record, identified by the receipt Id Metrc assigned.
+func (g *Genealogy) AddSalesReceiptPost(id int, r SalesReceiptPost) {
+	sale := SaleNode(fmt.Sprint(id))
+	g.AddNode(sale)
+	for _, t := range r.Transactions {
+		g.AddEdge(PackageNode(t.PackageLabel), sale)
+	}
+}
+
+// Has reports whether the node is in the graph.
+func (g *Genealogy) Has(n GenealogyNode) bool {
+	return g.nodes[n]
+}
+
+// Nodes returns every node, sorted by kind and name.
+func (g *Genealogy) Nodes() []GenealogyNode {
+	nodes := make([]GenealogyNode, 0, len(g.nodes))
+	for n := range g.nodes {
+		nodes = append(nodes, n)
+	}
+	sortNodes(nodes)
+	return nodes
+}
+
+// Edges returns every edge, sorted by source then target.
+func (g *Genealogy) Edges() []GenealogyEdge {
+	edges := []GenealogyEdge{}
+	for _, from := range g.Nodes() {
+		for _, to := range g.Children(from) {
+			edges = append(edges, GenealogyEdge{From: from, To: to})
+		}
+	}
+	return edges
+}
+
+// Parents returns the direct sources of a node.
+func (g *Genealogy) Parents(n GenealogyNode) []GenealogyNode {
+	return sortedSet(g.parents[n])
+}
+
+// Children returns what was made directly from a node.
+func (g *Genealogy) Children(n GenealogyNode) []GenealogyNode {
+	return sortedSet(g.children[n])
+}
+
+// Ancestors returns every node upstream of a node, back to its source plants and harvests.
+func (g *Genealogy) Ancestors(n GenealogyNode) []GenealogyNode {
+	return g.walk(n, g.parents)
+}
+
+// Descendants returns every node downstream of a node, forward to every derived package and sale.
+func (g *Genealogy) Descendants(n GenealogyNode) []GenealogyNode {
+	return g.walk(n, g.children)
+}
+
+// walk collects the nodes reachable from start, excluding start.
+func (g *Genealogy) walk(start GenealogyNode, edges map[GenealogyNode]map[GenealogyNode]bool) []GenealogyNode {
+	seen := map[GenealogyNode]bool{start: true}
+	queue := []GenealogyNode{start}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for next := range edges[n] {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	delete(seen, start)
+	return sortedSet(seen)
+}
+
+// DOT renders the graph in Graphviz DOT format.
+func (g *Genealogy) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph genealogy {\n")
+	for _, n := range g.Nodes() {
+		fmt.Fprintf(&b, "\t%q [label=%q, shape=%s];\n", n.String(), n.Name, dotShape(n.Kind))
+	}
+	for _, e := range g.Edges() {
+		fmt.Fprintf(&b, "\t%q -> %q;\n", e.From.String(), e.To.String())
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotShape(k GenealogyKind) string {
+	switch k {
+	case GenealogyPlantBatch:
+		return "invhouse"
+	case GenealogyPlant:
+		return "ellipse"
+	case GenealogyHarvest:
+		return "hexagon"
+	case GenealogySale:
+		return "note"
+	}
+	return "box"
+}
+
+// genealogyJSON is the JSON form of a `Genealogy`.
+type genealogyJSON struct {
+	Nodes []GenealogyNode `json:"Nodes"`
+	Edges []GenealogyEdge `json:"Edges"`
+}
+
+// MarshalJSON encodes the graph as its sorted nodes and edges.
+func (g *Genealogy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(genealogyJSON{Nodes: g.Nodes(), Edges: g.Edges()})
+}
+
+// UnmarshalJSON decodes a graph encoded by `MarshalJSON`, e.g. a saved snapshot.
+func (g *Genealogy) UnmarshalJSON(data []byte) error {
+	var gj genealogyJSON
+	err := json.Unmarshal(data, &gj)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal genealogy: %s", err)
+	}
+
+	*g = *MakeGenealogy()
+	for _, n := range gj.Nodes {
+		g.AddNode(n)
+	}
+	for _, e := range gj.Edges {
+		g.AddEdge(e.From, e.To)
+	}
+	return nil
+}
+
+func sortedSet(set map[GenealogyNode]bool) []GenealogyNode {
+	nodes := make([]GenealogyNode, 0, len(set))
+	for n := range set {
+		nodes = append(nodes, n)
+	}
+	sortNodes(nodes)
+	return nodes
+}
+
+func sortNodes(nodes []GenealogyNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Kind != nodes[j].Kind {
+			return nodes[i].Kind < nodes[j].Kind
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+// GenealogyScope is how far `BuildGenealogy` reaches beyond Packages.
+// Each reach scans whole lists from Metrc rather than following links, so it is opt in.
+type GenealogyScope struct {
+	// Plants adds the Plants harvested into every harvest in the genealogy, and their plant batches.
+	// Harvest Ids are looked up among the active, on hold, and inactive harvests, and Plants among the flowering and inactive Plants.
+	Plants bool
+
+	// SalesDateStart and SalesDateEnd, when both set, add the active sales receipts sold in that range that sold a Package
+	// in the genealogy. Receipts listed without their transactions are fetched one at a time.
+	SalesDateStart *string
+	SalesDateEnd   *string
+}
+
+// BuildGenealogy builds the genealogy of Packages from Metrc. It walks back through their source Packages and harvests
+// with `GetPackagesSourcePackages` and `GetPackagesSourceHarvests`, and forward through derived Packages with `GetPackagesChildren`,
+// then reaches back to Plants and forward to sales receipts as the scope allows.
+// Siblings, i.e. other Packages made from the same sources, are not included.
+func (m *Metrc) BuildGenealogy(labels []string, licenseNumber string, scope GenealogyScope) (*Genealogy, error) {
+	b := m.makeGenealogyBuilder(licenseNumber)
+
+	err := b.walkSources(labels)
+	if err != nil {
+		return b.g, err
+	}
+	err = b.walkChildren(labels)
+	if err != nil {
+		return b.g, err
+	}
+
+	if scope.Plants {
+		err = b.addPlants()
+		if err != nil {
+			return b.g, err
+		}
+	}
+	if scope.SalesDateStart != nil && scope.SalesDateEnd != nil {
+		err = b.addSales(*scope.SalesDateStart, *scope.SalesDateEnd)
+		if err != nil {
+			return b.g, err
+		}
+	}
+
+	return b.g, nil
+}
+
+// BuildGenealogyDescendants builds only the downstream genealogy of Packages: the Packages derived from them, found with
+// `GetPackagesChildren`, and sales receipts as the scope allows. Unlike `BuildGenealogy` it does not walk back to their sources,
+// so it makes fewer calls, e.g. for a recall. Plants are upstream of Packages, so `GenealogyScope.Plants` is ignored.
+func (m *Metrc) BuildGenealogyDescendants(labels []string, licenseNumber string, scope GenealogyScope) (*Genealogy, error) {
+	b := m.makeGenealogyBuilder(licenseNumber)
+
+	err := b.walkChildren(labels)
+	if err != nil {
+		return b.g, err
+	}
+
+	if scope.SalesDateStart != nil && scope.SalesDateEnd != nil {
+		err = b.addSales(*scope.SalesDateStart, *scope.SalesDateEnd)
+		if err != nil {
+			return b.g, err
+		}
+	}
+
+	return b.g, nil
+}
+
+// genealogyBuilder fetches the links of a genealogy from Metrc, looking each Package up once.
+type genealogyBuilder struct {
+	m             *Metrc
+	licenseNumber string
+	g             *Genealogy
+	packages      map[string]PackageGet
+}
+
+func (m *Metrc) makeGenealogyBuilder(licenseNumber string) *genealogyBuilder {
+	return &genealogyBuilder{
+		m:             m,
+		licenseNumber: licenseNumber,
+		g:             MakeGenealogy(),
+		packages:      map[string]PackageGet{},
+	}
+}
+
+func (b *genealogyBuilder) getPackage(label string) (PackageGet, error) {
+	if p, ok := b.packages[label]; ok {
+		return p, nil
+	}
+	p, err := b.m.GetPackagesByLabel(label, &b.licenseNumber)
+	if err != nil {
+		return p, fmt.Errorf("could not get package %s: %s", label, err)
+	}
+	b.packages[label] = p
+	b.g.AddPackage(p)
+	return p, nil
+}
+
+// walkSources walks back from Packages to their source Packages and harvests.
+func (b *genealogyBuilder) walkSources(labels []string) error {
+	visited := map[string]bool{}
+	queue := append([]string{}, labels...)
+	for len(queue) > 0 {
+		label := queue[0]
+		queue = queue[1:]
+		if visited[label] {
+			continue
+		}
+		visited[label] = true
+
+		p, err := b.getPackage(label)
+		if err != nil {
+			return err
+		}
+
+		harvests, err := b.m.GetPackagesSourceHarvests(p.Id, b.licenseNumber)
+		if err != nil {
+			return err
+		}
+		for _, h := range harvests {
+			b.g.AddEdge(HarvestNode(h.HarvestName), PackageNode(p.Label))
+		}
+
+		sources, err := b.m.GetPackagesSourcePackages(p.Id, b.licenseNumber)
+		if err != nil {
+			return err
+		}
+		for _, s := range sources {
+			b.g.AddEdge(PackageNode(s.PackageLabel), PackageNode(p.Label))
+			queue = append(queue, s.PackageLabel)
+		}
+	}
+	return nil
+}
+
+// walkChildren walks forward from Packages to the Packages derived from them.
+func (b *genealogyBuilder) walkChildren(labels []string) error {
+	visited := map[string]bool{}
+	queue := append([]string{}, labels...)
+	for len(queue) > 0 {
+		label := queue[0]
+		queue = queue[1:]
+		if visited[label] {
+			continue
+		}
+		visited[label] = true
+
+		p, err := b.getPackage(label)
+		if err != nil {
+			return err
+		}
+
+		children, err := b.m.GetPackagesChildren(p.Id, b.licenseNumber)
+		if err != nil {
+			return err
+		}
+		for _, c := range children {
+			b.g.AddEdge(PackageNode(p.Label), PackageNode(c.PackageLabel))
+			queue = append(queue, c.PackageLabel)
+		}
+	}
+	return nil
+}
+
+// addPlants adds the Plants harvested into the harvests of the genealogy.
+func (b *genealogyBuilder) addPlants() error {
+	names := map[string]bool{}
+	for _, n := range b.g.Nodes() {
+		if n.Kind == GenealogyHarvest {
+			names[n.Name] = true
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	harvests := map[int]string{}
+	for _, get := range []func(string, *string, *string) ([]Harvest, error){b.m.GetHarvestsActive, b.m.GetHarvestsOnHold, b.m.GetHarvestsInactive} {
+		hs, err := get(b.licenseNumber, nil, nil)
+		if err != nil {
+			return fmt.Errorf("could not get harvests: %s", err)
+		}
+		for _, h := range hs {
+			if h.Id != nil && names[h.Name] {
+				harvests[*h.Id] = h.Name
+			}
+		}
+	}
+	if len(harvests) == 0 {
+		return nil
+	}
+
+	for _, it := range []*PlantIterator{b.m.IteratePlantsFlowering(b.licenseNumber, nil, nil), b.m.IteratePlantsInactive(b.licenseNumber, nil, nil)} {
+		for it.Next() {
+			p := it.Item()
+			if p.HarvestId == nil {
+				continue
+			}
+			if name, ok := harvests[*p.HarvestId]; ok {
+				b.g.AddPlant(p)
+				b.g.AddEdge(PlantNode(p.Label), HarvestNode(name))
+			}
+		}
+		if it.Err() != nil {
+			return fmt.Errorf("could not get plants: %s", it.Err())
+		}
+	}
+	return nil
+}
+
+// addSales adds the active receipts sold between start and end that sold a Package of the genealogy.
+func (b *genealogyBuilder) addSales(salesDateStart string, salesDateEnd string) error {
+	labels := map[string]bool{}
+	for _, n := range b.g.Nodes() {
+		if n.Kind == GenealogyPackage {
+			labels[n.Name] = true
+		}
+	}
+
+	receipts, err := b.m.salesReceiptsSelling(labels, b.licenseNumber, salesDateStart, salesDateEnd)
+	if err != nil {
+		return err
+	}
+	for _, r := range receipts {
+		b.g.AddSalesReceipt(r)
+	}
+	return nil
+}
+
+// salesReceiptsSelling returns the active receipts sold between start and end that sold any of the labels.
+// Receipts listed without their transactions are fetched with `GetSalesReceiptsById`.
+func (m *Metrc) salesReceiptsSelling(labels map[string]bool, licenseNumber string, salesDateStart string, salesDateEnd string) ([]SalesReceiptGet, error) {
+	var receipts []SalesReceiptGet
+	it := m.IterateSalesReceiptsActive(licenseNumber, &salesDateStart, &salesDateEnd, nil, nil)
+	defer it.Close()
+	for it.Next() {
+		r := it.Item()
+		if len(r.Transactions) == 0 && r.TotalPackages > 0 {
+			full, err := m.GetSalesReceiptsById(r.Id, &licenseNumber)
+			if err != nil {
+				return receipts, fmt.Errorf("could not get receipt %d: %s", r.Id, err)
+			}
+			r = full
+		}
+
+		for _, t := range r.Transactions {
+			if labels[t.PackageLabel] {
+				receipts = append(receipts, r)
+				break
+			}
+		}
+	}
+	if it.Err() != nil {
+		return receipts, fmt.Errorf("could not get active receipts: %s", it.Err())
+	}
+
+	return receipts, nil
+}
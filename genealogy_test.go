@@ -0,0 +1,99 @@
+package metrc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenealogySnapshot(t *testing.T) {
+	harvestNames := "Harvest A"
+	sourceLabels := "PKG-2"
+	g := MakeGenealogy()
+	g.AddPlantHarvests([]PlantHarvest{{Plant: "PLANT-1", HarvestName: "Harvest A"}, {Plant: "PLANT-2", HarvestName: "Harvest B"}})
+	g.AddPackage(PackageGet{Label: "PKG-1", SourceHarvestNames: &harvestNames})
+	g.AddPackagePosts([]PackagePost{{Tag: "PKG-2", Ingredients: []Ingredient{{Package: "PKG-1"}}}})
+	g.AddTransferPackage(TransferDeliveryPackage{PackageLabel: "PKG-3", SourcePackageLabels: &sourceLabels})
+	g.AddSalesReceiptPost(7, SalesReceiptPost{Transactions: []SalesTransactionPost{{PackageLabel: "PKG-3"}}})
+	g.AddSalesReceipt(SalesReceiptGet{Id: 8, Transactions: []SalesReceiptTransaction{{PackageLabel: "PKG-2"}}})
+
+	assert.Equal(t, []GenealogyNode{
+		HarvestNode("Harvest A"),
+		PackageNode("PKG-1"),
+		PackageNode("PKG-2"),
+		PlantNode("PLANT-1"),
+	}, g.Ancestors(PackageNode("PKG-3")))
+	assert.Equal(t, []GenealogyNode{
+		PackageNode("PKG-1"),
+		PackageNode("PKG-2"),
+		PackageNode("PKG-3"),
+		SaleNode("7"),
+		SaleNode("8"),
+	}, g.Descendants(HarvestNode("Harvest A")))
+	assert.Equal(t, []GenealogyNode{HarvestNode("Harvest B")}, g.Descendants(PlantNode("PLANT-2")))
+
+	dot := g.DOT()
+	assert.Contains(t, dot, `"Package PKG-2" -> "Package PKG-3";`)
+	assert.Contains(t, dot, `"Sale 7" [label="7", shape=note];`)
+
+	data, err := json.Marshal(g)
+	assert.NoError(t, err)
+	restored := MakeGenealogy()
+	assert.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, g.Nodes(), restored.Nodes())
+	assert.Equal(t, g.Edges(), restored.Edges())
+}
+
+func TestGenealogyBuild(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"packages/v1/PKG-1":             `{"Id": 1, "Label": "PKG-1"}`,
+		"packages/v1/PKG-2":             `{"Id": 2, "Label": "PKG-2"}`,
+		"packages/v1/PKG-3":             `{"Id": 3, "Label": "PKG-3"}`,
+		"packages/v1/1/source/harvests": `[{"HarvestName": "Harvest A"}]`,
+		"packages/v1/1/source/packages": `[]`,
+		"packages/v1/2/source/harvests": `[]`,
+		"packages/v1/2/source/packages": `[{"PackageLabel": "PKG-1"}]`,
+		"packages/v1/2/children":        `[{"PackageLabel": "PKG-3"}]`,
+		"packages/v1/3/children":        `[]`,
+	})
+	g, err := (&Metrc{Client: fc}).BuildGenealogy([]string{"PKG-2"}, licenseNumber, GenealogyScope{})
+	assert.NoError(t, err)
+	assert.Equal(t, []GenealogyNode{HarvestNode("Harvest A"), PackageNode("PKG-1")}, g.Ancestors(PackageNode("PKG-2")))
+	assert.Equal(t, []GenealogyNode{PackageNode("PKG-3")}, g.Descendants(PackageNode("PKG-2")))
+}
+
+func TestGenealogyBuildScope(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"packages/v1/PKG-1":             `{"Id": 1, "Label": "PKG-1"}`,
+		"packages/v1/1/source/harvests": `[{"HarvestName": "Harvest A"}]`,
+		"packages/v1/1/source/packages": `[]`,
+		"packages/v1/1/children":        `[]`,
+		"harvests/v1/active":            `[{"Id": 5, "Name": "Harvest A"}]`,
+		"harvests/v1/onhold":            `[]`,
+		"harvests/v1/inactive":          `[{"Id": 6, "Name": "Harvest B"}]`,
+		"plants/v1/flowering":           `[{"Label": "PLANT-1", "HarvestId": 5, "PlantBatchName": "Batch 1"}]`,
+		"plants/v1/inactive":            `[{"Label": "PLANT-2", "HarvestId": 6}, {"Label": "PLANT-3", "HarvestId": 5}]`,
+		"sales/v1/receipts/active":      `[{"Id": 30, "TotalPackages": 1}, {"Id": 31, "Transactions": [{"PackageLabel": "PKG-9"}]}]`,
+		"sales/v1/receipts/30":          `{"Id": 30, "Transactions": [{"PackageLabel": "PKG-1"}]}`,
+	})
+	m := &Metrc{Client: fc}
+	start, end := "2021-06-01", "2021-06-02"
+
+	g, err := m.BuildGenealogy([]string{"PKG-1"}, licenseNumber, GenealogyScope{Plants: true, SalesDateStart: &start, SalesDateEnd: &end})
+	assert.NoError(t, err)
+	assert.Equal(t, []GenealogyNode{
+		HarvestNode("Harvest A"),
+		PackageNode("PKG-1"),
+		PlantNode("PLANT-1"),
+		PlantNode("PLANT-3"),
+		PlantBatchNode("Batch 1"),
+	}, g.Ancestors(SaleNode("30")))
+	assert.False(t, g.Has(SaleNode("31")))
+	assert.False(t, g.Has(PlantNode("PLANT-2")))
+
+	fc.responses["sales/v1/receipts/active"] = `[{"Id": 32, "TotalPackages": 1}]`
+	_, err = m.BuildGenealogy([]string{"PKG-1"}, licenseNumber, GenealogyScope{SalesDateStart: &start, SalesDateEnd: &end})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not get receipt 32")
+}
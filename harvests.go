@@ -17,6 +17,8 @@ type Harvest struct {
 	DryingLocationId       int      `json:"DryingLocationId"`
 	DryingLocationName     string   `json:"DryingLocationName"`
 	DryingLocationTypeName *string  `json:"DryingLocationTypeName"`
+	DryingSublocationId    *int     `json:"DryingSublocationId"`
+	DryingSublocationName  *string  `json:"DryingSublocationName"`
 	PatientLicenseNumber   *string  `json:"PatientLicenseNumber"`
 	CurrentWeight          float64  `json:"CurrentWeight"`
 	TotalWasteWeight       float64  `json:"TotalWasteWeight"`
@@ -59,26 +61,12 @@ func (m *Metrc) GetHarvestsById(id int, licenseNumber *string) (Harvest, error)
 
 // helper method to get harvests with the "status" endpoint
 func (m *Metrc) getHarvestsByStatus(status string, licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) ([]Harvest, error) {
-	endpoint := fmt.Sprintf("harvests/v1/%s", status)
-	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
-	if lastModifiedStart != nil {
-		endpoint += fmt.Sprintf("?lastModifiedStart=%s", *lastModifiedStart)
-	}
-	if lastModifiedEnd != nil {
-		endpoint += fmt.Sprintf("?lastModifiedEnd=%s", *lastModifiedEnd)
-	}
-
 	var hr []Harvest
-	responseBody, err := m.Client.Get(endpoint)
+	err := m.getList("harvests", status, licenseNumber, lastModifiedParams(lastModifiedStart, lastModifiedEnd), &hr)
 	if err != nil {
 		return hr, fmt.Errorf("could not get harvests by status response: %s", err)
 	}
 
-	err = json.Unmarshal(responseBody, &hr)
-	if err != nil {
-		return hr, fmt.Errorf("could not unmarshal response: %s", err)
-	}
-
 	return hr, nil
 }
 
@@ -154,6 +142,7 @@ type HarvestIngredient struct {
 type HarvestPackagePost struct {
 	Tag                        string              `json:"Tag"`
 	Location                   *string             `json:"Location"`
+	Sublocation                *string             `json:"Sublocation,omitempty"`
 	Item                       string              `json:"Item"`
 	UnitOfWeight               string              `json:"UnitOfWeight"`
 	PatientLicenseNumber       string              `json:"PatientLicenseNumber"`
@@ -169,11 +158,26 @@ type HarvestPackagePost struct {
 	RemediationSteps           *[]string           `json:"RemediationSteps"`
 	ActualDate                 string              `json:"ActualDate"`
 	Ingredients                []HarvestIngredient `json:"Ingredients"`
+	RequiredLabTestBatches     []string            `json:"RequiredLabTestBatches,omitempty"` // Only used by `PostHarvestsCreatePackagesTesting`.
 }
 
 // PostHarvestsCreatePackages creates a new package in a harvest in Metrc.
 // See: https://testing-api-ca.metrc.com/Documentation#Harvests.post_harvests_v1_create_packages.POST
 func (m *Metrc) PostHarvestsCreatePackages(packages []HarvestPackagePost, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packages {
+		labels = append(labels, p.Tag)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "create harvest packages", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "harvests/v1/create/packages"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -191,8 +195,23 @@ func (m *Metrc) PostHarvestsCreatePackages(packages []HarvestPackagePost, licens
 }
 
 // PostHarvestsCreatePackagesTesting creates a new package for testing in a harvest in Metrc.
+// Set `HarvestPackagePost.RequiredLabTestBatches` to names from `GetLabTestsBatches` when the facility `CanRequireHarvestSampleLabTestBatches`.
 // See: https://testing-api-ca.metrc.com/Documentation#Harvests.post_harvests_v1_create_packages_testing.POST
 func (m *Metrc) PostHarvestsCreatePackagesTesting(packages []HarvestPackagePost, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packages {
+		labels = append(labels, p.Tag)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "create harvest testing packages", "CanGrowPlants", "CanSubmitHarvestsForTesting")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "harvests/v1/create/packages/testing"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -212,15 +231,21 @@ func (m *Metrc) PostHarvestsCreatePackagesTesting(packages []HarvestPackagePost,
 // HarvestMove represents a harvest to move.
 // See: https://testing-api-ca.metrc.com/Documentation#Harvests.put_harvests_v1_move.PUT
 type HarvestMove struct {
-	Id             *int    `json:"Id"`
-	HarvestName    *string `json:"HarvestName"`
-	DryingLocation string  `json:"DryingLocation"`
-	ActualDate     string  `json:"ActualDate"`
+	Id                *int    `json:"Id"`
+	HarvestName       *string `json:"HarvestName"`
+	DryingLocation    string  `json:"DryingLocation"`
+	DryingSublocation *string `json:"DryingSublocation,omitempty"`
+	ActualDate        string  `json:"ActualDate"`
 }
 
 // PutHarvestsMove is used to move harvests in Metrc.
 // See: https://testing-api-ca.metrc.com/Documentation#Harvests.put_harvests_v1_move.PUT
 func (m *Metrc) PutHarvestsMove(harvests []HarvestMove, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "move harvests", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "harvests/v1/move"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -250,6 +275,11 @@ type HarvestRemoveWaste struct {
 // PostHarvestsRemoveWaste removes wastes from harvests.
 // See: https://testing-api-ca.metrc.com/Documentation#Harvests.post_harvests_v1_removewaste.POST
 func (m *Metrc) PostHarvestsRemoveWaste(wastes []HarvestRemoveWaste, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "remove harvest waste", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "harvests/v1/removewaste"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -277,6 +307,11 @@ type HarvestRename struct {
 // PutHarvestsRename renames a list of harvests in Metrc.
 // See: https://testing-api-ca.metrc.com/Documentation#Harvests.put_harvests_v1_rename.PUT
 func (m *Metrc) PutHarvestsRename(harvests []HarvestRename, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "rename harvests", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "harvests/v1/rename"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -303,6 +338,11 @@ type HarvestFinish struct {
 // PostHarvestsFinish finishes a list of harvests in Metrc.
 // See: https://testing-api-ca.metrc.com/Documentation#Harvests.post_harvests_v1_finish.POST
 func (m *Metrc) PostHarvestsFinish(harvests []HarvestFinish, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "finish harvests", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "harvests/v1/finish"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -328,6 +368,11 @@ type HarvestUnfinish struct {
 // PostHarvestsUnfinish unfinishes a list of harvests in Metrc.
 // See: https://testing-api-ca.metrc.com/Documentation#Harvests.post_harvests_v1_unfinish.POST
 func (m *Metrc) PostHarvestsUnfinish(harvests []HarvestUnfinish, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "unfinish harvests", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "harvests/v1/unfinish"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -343,3 +388,92 @@ func (m *Metrc) PostHarvestsUnfinish(harvests []HarvestUnfinish, licenseNumber s
 
 	return resp, nil
 }
+
+// HarvestWaste represents waste recorded against a harvest.
+// See: https://testing-api-ca.metrc.com/Documentation#Harvests.get_harvests_v1_waste.GET
+type HarvestWaste struct {
+	Id               int     `json:"Id"`
+	HarvestId        int     `json:"HarvestId"`
+	WasteTypeName    string  `json:"WasteTypeName"`
+	UnitOfWeightName string  `json:"UnitOfWeightName"`
+	WasteWeight      float64 `json:"WasteWeight"`
+	ActualDate       string  `json:"ActualDate"`
+}
+
+// GetHarvestsWaste gets the waste recorded for a harvest.
+// See: https://testing-api-ca.metrc.com/Documentation#Harvests.get_harvests_v1_waste.GET
+func (m *Metrc) GetHarvestsWaste(harvestId int, licenseNumber string) ([]HarvestWaste, error) {
+	endpoint := "harvests/v1/waste"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+	endpoint += fmt.Sprintf("&harvestId=%d", harvestId)
+
+	var hw []HarvestWaste
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return hw, fmt.Errorf("could not get harvest waste response: %s", err)
+	}
+
+	err = json.Unmarshal(responseBody, &hw)
+	if err != nil {
+		return hw, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return hw, nil
+}
+
+// DeleteHarvestsWasteById deletes a waste entry recorded against a harvest.
+// See: https://testing-api-ca.metrc.com/Documentation#Harvests.delete_harvests_v1_removewaste_{id}.DELETE
+func (m *Metrc) DeleteHarvestsWasteById(id int, licenseNumber string) ([]byte, error) {
+	endpoint := fmt.Sprintf("harvests/v1/removewaste/%d", id)
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	resp, err := m.Client.Delete(endpoint)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed deleting harvest waste %d: %s", id, err)
+	}
+
+	return resp, nil
+}
+
+// HarvestRestorePlant represents a harvested plant to restore back into a harvest.
+// See: https://testing-api-ca.metrc.com/Documentation#Harvests.post_harvests_v1_restore_harvestedplants.POST
+type HarvestRestorePlant struct {
+	HarvestId    int      `json:"HarvestId"`
+	PlantLabels  []string `json:"PlantLabels"`
+	Weight       float64  `json:"Weight"`
+	UnitOfWeight string   `json:"UnitOfWeight"`
+}
+
+// PostHarvestsRestorePlants restores harvested plants.
+// Metrc only accepts restorations within `FacilitiesFacilityType.RestrictHarvestPlantRestoreTimeHours` of the harvest.
+// See: https://testing-api-ca.metrc.com/Documentation#Harvests.post_harvests_v1_restore_harvestedplants.POST
+func (m *Metrc) PostHarvestsRestorePlants(plants []HarvestRestorePlant, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range plants {
+		labels = append(labels, p.PlantLabels...)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "restore harvested plants", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := "harvests/v1/restore/harvestedplants"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(plants)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal restore plants: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed posting restore plants: %s", err)
+	}
+
+	return resp, nil
+}
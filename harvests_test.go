@@ -69,3 +69,15 @@ func TestHarvestsFinish_Integration(t *testing.T) {
 func TestHarvestsUnfinish_Integration(t *testing.T) {
 	// TODO: Implement.
 }
+
+func TestHarvestsWaste_Integration(t *testing.T) {
+	// TODO: Implement.
+}
+
+func TestHarvestsDeleteWaste_Integration(t *testing.T) {
+	// TODO: Implement.
+}
+
+func TestHarvestsRestorePlants_Integration(t *testing.T) {
+	// TODO: Implement.
+}
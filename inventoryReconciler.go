@@ -0,0 +1,247 @@
+package metrc
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// InventoryItem is a Package as another system of record, e.g. a POS, holds it.
+type InventoryItem struct {
+	Label         string
+	Quantity      float64
+	UnitOfMeasure string
+
+	// Location is not compared if empty.
+	Location string
+}
+
+// InventoryDiscrepancyKind is how a Package differs between a local snapshot and Metrc.
+type InventoryDiscrepancyKind string
+
+// The kinds of inventory discrepancies.
+const (
+	InventoryMissing          InventoryDiscrepancyKind = "missing"           // In the snapshot, but neither active nor on hold in Metrc.
+	InventoryExtra            InventoryDiscrepancyKind = "extra"             // Active or on hold in Metrc, but not in the snapshot.
+	InventoryOnHold           InventoryDiscrepancyKind = "on hold"           // In the snapshot, but on hold in Metrc.
+	InventoryQuantityMismatch InventoryDiscrepancyKind = "quantity mismatch" // Quantities differ after unit conversion.
+	InventoryUnitMismatch     InventoryDiscrepancyKind = "unit mismatch"     // The snapshot's unit cannot be converted to the Package's.
+	InventoryLocationMismatch InventoryDiscrepancyKind = "location mismatch" // Locations differ.
+)
+
+// InventoryDiscrepancy is a Package that differs between a local snapshot and Metrc.
+// Quantities are in the Metrc Package's unit, except for missing and on hold Packages and unit mismatches, which keep the snapshot's unit.
+type InventoryDiscrepancy struct {
+	Kind          InventoryDiscrepancyKind
+	Label         string
+	LocalQuantity float64
+	MetrcQuantity float64
+	UnitOfMeasure string
+	LocalLocation string
+	MetrcLocation string
+	Message       string
+}
+
+func (d InventoryDiscrepancy) String() string {
+	return fmt.Sprintf("%s %s: %s", d.Kind, d.Label, d.Message)
+}
+
+// InventoryReconciliation is the result of comparing a local snapshot with Metrc, and the writes proposed to bring
+// Metrc in line with the snapshot.
+// Only quantity and location mismatches have proposed writes: missing, extra, and on hold Packages need to be looked at by hand.
+type InventoryReconciliation struct {
+	Discrepancies []InventoryDiscrepancy
+	Adjusts       []PackageAdjust
+	Locations     []PackageLocation
+}
+
+// Preview describes the discrepancies and proposed writes without making them, as a dry run.
+func (r InventoryReconciliation) Preview() string {
+	var b strings.Builder
+	if len(r.Discrepancies) == 0 {
+		b.WriteString("no discrepancies\n")
+	}
+	for _, d := range r.Discrepancies {
+		fmt.Fprintf(&b, "%s\n", d)
+	}
+	for _, a := range r.Adjusts {
+		fmt.Fprintf(&b, "would adjust %s by %+g %s (%s)\n", a.Label, a.Quantity, a.UnitOfMeasure, a.AdjustmentReason)
+	}
+	for _, l := range r.Locations {
+		fmt.Fprintf(&b, "would move %s to %s\n", l.Label, l.Location)
+	}
+	return b.String()
+}
+
+// InventoryReconciler compares a local inventory snapshot with the active and on hold Packages of a license.
+// Proposed adjustments use AdjustmentReason and ReasonNote, and quantities within Tolerance, in the Package's unit, match.
+type InventoryReconciler struct {
+	Metrc            *Metrc
+	LicenseNumber    string
+	AdjustmentReason string
+	ReasonNote       *string
+	Tolerance        float64
+}
+
+// MakeInventoryReconciler creates a reconciler for the Packages of a license, proposing adjustments for the given reason.
+func MakeInventoryReconciler(m *Metrc, licenseNumber string, adjustmentReason string) *InventoryReconciler {
+	return &InventoryReconciler{
+		Metrc:            m,
+		LicenseNumber:    licenseNumber,
+		AdjustmentReason: adjustmentReason,
+	}
+}
+
+// Reconcile compares a snapshot with `GetPackagesActive` and `GetPackagesOnHold` and proposes adjustments and moves dated on date.
+// Packages on hold cannot be adjusted or moved, so they are only reported.
+// Each label may appear once in the snapshot.
+func (rc *InventoryReconciler) Reconcile(snapshot []InventoryItem, date string) (InventoryReconciliation, error) {
+	var r InventoryReconciliation
+
+	local := map[string]InventoryItem{}
+	for _, item := range snapshot {
+		if _, ok := local[item.Label]; ok {
+			return r, fmt.Errorf("package %s is in the snapshot more than once", item.Label)
+		}
+		local[item.Label] = item
+	}
+
+	active, err := rc.Metrc.GetPackagesActive(rc.LicenseNumber, nil, nil)
+	if err != nil {
+		return r, fmt.Errorf("could not get active packages: %s", err)
+	}
+	onHold, err := rc.Metrc.GetPackagesOnHold(rc.LicenseNumber, nil, nil)
+	if err != nil {
+		return r, fmt.Errorf("could not get on hold packages: %s", err)
+	}
+	listed := append(active, onHold...)
+	packages := map[string]PackageGet{}
+	for _, p := range listed {
+		packages[p.Label] = p
+	}
+
+	for _, item := range snapshot {
+		p, ok := packages[item.Label]
+		if !ok {
+			r.Discrepancies = append(r.Discrepancies, InventoryDiscrepancy{
+				Kind:          InventoryMissing,
+				Label:         item.Label,
+				LocalQuantity: item.Quantity,
+				UnitOfMeasure: item.UnitOfMeasure,
+				LocalLocation: item.Location,
+				Message:       fmt.Sprintf("%g %s is not active in Metrc", item.Quantity, item.UnitOfMeasure),
+			})
+			continue
+		}
+		if p.IsOnHold {
+			d := InventoryDiscrepancy{
+				Kind:          InventoryOnHold,
+				Label:         item.Label,
+				LocalQuantity: item.Quantity,
+				MetrcQuantity: p.Quantity,
+				UnitOfMeasure: p.UnitOfMeasureName,
+				LocalLocation: item.Location,
+				Message:       "is on hold in Metrc",
+			}
+			if p.LocationName != nil {
+				d.MetrcLocation = *p.LocationName
+			}
+			r.Discrepancies = append(r.Discrepancies, d)
+			continue
+		}
+		rc.compare(&r, item, p, date)
+	}
+
+	for _, p := range listed {
+		if _, ok := local[p.Label]; ok {
+			continue
+		}
+		d := InventoryDiscrepancy{
+			Kind:          InventoryExtra,
+			Label:         p.Label,
+			MetrcQuantity: p.Quantity,
+			UnitOfMeasure: p.UnitOfMeasureName,
+			Message:       fmt.Sprintf("%g %s is not in the snapshot", p.Quantity, p.UnitOfMeasureName),
+		}
+		if p.LocationName != nil {
+			d.MetrcLocation = *p.LocationName
+		}
+		r.Discrepancies = append(r.Discrepancies, d)
+	}
+
+	sort.SliceStable(r.Discrepancies, func(i, j int) bool { return r.Discrepancies[i].Label < r.Discrepancies[j].Label })
+	return r, nil
+}
+
+// compare adds the discrepancies and proposed writes for a Package in both the snapshot and Metrc.
+func (rc *InventoryReconciler) compare(r *InventoryReconciliation, item InventoryItem, p PackageGet, date string) {
+	metrcLocation := ""
+	if p.LocationName != nil {
+		metrcLocation = *p.LocationName
+	}
+	d := InventoryDiscrepancy{
+		Label:         item.Label,
+		MetrcQuantity: p.Quantity,
+		UnitOfMeasure: p.UnitOfMeasureName,
+		LocalLocation: item.Location,
+		MetrcLocation: metrcLocation,
+	}
+
+	// Units Metrc names exactly are not converted, so units the converter does not know still compare.
+	quantity := item.Quantity
+	var err error
+	if unit := item.UnitOfMeasure; unit != "" && unit != p.UnitOfMeasureName && unit != p.UnitOfMeasureAbbreviation {
+		quantity, err = ConvertQuantity(item.Quantity, unit, p.UnitOfMeasureName)
+	}
+	d.LocalQuantity = quantity
+	if err != nil {
+		d.Kind = InventoryUnitMismatch
+		d.LocalQuantity = item.Quantity
+		d.Message = err.Error()
+		r.Discrepancies = append(r.Discrepancies, d)
+	} else if diff := quantity - p.Quantity; math.Abs(diff) > rc.Tolerance+1e-9 {
+		d.Kind = InventoryQuantityMismatch
+		d.Message = fmt.Sprintf("snapshot has %g %s, Metrc has %g %s", quantity, p.UnitOfMeasureName, p.Quantity, p.UnitOfMeasureName)
+		r.Discrepancies = append(r.Discrepancies, d)
+		r.Adjusts = append(r.Adjusts, PackageAdjust{
+			Label:            p.Label,
+			Quantity:         diff,
+			UnitOfMeasure:    p.UnitOfMeasureName,
+			AdjustmentReason: rc.AdjustmentReason,
+			AdjustmentDate:   date,
+			ReasonNote:       rc.ReasonNote,
+		})
+	}
+
+	if item.Location != "" && !strings.EqualFold(strings.TrimSpace(item.Location), strings.TrimSpace(metrcLocation)) {
+		d.Kind = InventoryLocationMismatch
+		d.Message = fmt.Sprintf("snapshot has location %q, Metrc has %q", item.Location, metrcLocation)
+		r.Discrepancies = append(r.Discrepancies, d)
+		r.Locations = append(r.Locations, PackageLocation{
+			Label:    p.Label,
+			Location: strings.TrimSpace(item.Location),
+			MoveDate: date,
+		})
+	}
+}
+
+// Apply makes the proposed writes of a reconciliation: adjustments are validated and posted with a `PackageAdjuster`,
+// then moves are posted with `PostPackagesChangeLocations`. Use `Preview` for a dry run.
+func (rc *InventoryReconciler) Apply(r InventoryReconciliation) error {
+	if len(r.Adjusts) > 0 {
+		_, err := MakePackageAdjuster(rc.Metrc, rc.LicenseNumber).Adjust(r.Adjusts)
+		if err != nil {
+			return fmt.Errorf("could not adjust packages: %s", err)
+		}
+	}
+
+	if len(r.Locations) > 0 {
+		_, err := rc.Metrc.PostPackagesChangeLocations(r.Locations, rc.LicenseNumber)
+		if err != nil {
+			return fmt.Errorf("could not move packages: %s", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package metrc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInventoryReconcile(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"packages/v1/active": `[
+			{"Id": 1, "Label": "PKG-1", "Quantity": 1000, "UnitOfMeasureName": "Grams", "UnitOfMeasureAbbreviation": "g", "LocationName": "Vault"},
+			{"Id": 2, "Label": "PKG-2", "Quantity": 10, "UnitOfMeasureName": "Grams", "UnitOfMeasureAbbreviation": "g", "LocationName": "Vault"},
+			{"Id": 3, "Label": "PKG-3", "Quantity": 5, "UnitOfMeasureName": "Each", "UnitOfMeasureAbbreviation": "ea", "LocationName": "Floor"},
+			{"Id": 4, "Label": "PKG-4", "Quantity": 2, "UnitOfMeasureName": "Each", "UnitOfMeasureAbbreviation": "ea", "LocationName": "Floor"},
+			{"Id": 8, "Label": "PKG-8", "Quantity": 3, "UnitOfMeasureName": "Packs", "UnitOfMeasureAbbreviation": "pk", "LocationName": "Floor"}
+		]`,
+		"packages/v1/onhold": `[
+			{"Id": 6, "Label": "PKG-6", "Quantity": 1, "UnitOfMeasureName": "Each", "IsOnHold": true},
+			{"Id": 7, "Label": "PKG-7", "Quantity": 1, "UnitOfMeasureName": "Each", "IsOnHold": true}
+		]`,
+		"packages/v1/adjust/reasons": `[{"Name": "Inventory Count", "RequiresNote": false}]`,
+		"packages/v1/PKG-2":          `{"Id": 2, "Label": "PKG-2", "Quantity": 10, "UnitOfMeasureName": "Grams"}`,
+	})
+	m := &Metrc{Client: fc}
+	rc := MakeInventoryReconciler(m, licenseNumber, "Inventory Count")
+
+	_, err := rc.Reconcile([]InventoryItem{{Label: "PKG-1"}, {Label: "PKG-1"}}, "2026-10-19")
+	assert.Error(t, err)
+
+	r, err := rc.Reconcile([]InventoryItem{
+		{Label: "PKG-1", Quantity: 1, UnitOfMeasure: "kg", Location: "vault"},
+		{Label: "PKG-2", Quantity: 8, UnitOfMeasure: "g", Location: "Back Room"},
+		{Label: "PKG-3", Quantity: 5, UnitOfMeasure: "Grams"},
+		{Label: "PKG-5", Quantity: 1, UnitOfMeasure: "Each"},
+		{Label: "PKG-6", Quantity: 2, UnitOfMeasure: "Each"},
+		{Label: "PKG-8", Quantity: 3, UnitOfMeasure: "pk"},
+	}, "2026-10-19")
+	assert.NoError(t, err)
+
+	var kinds []InventoryDiscrepancyKind
+	for _, d := range r.Discrepancies {
+		kinds = append(kinds, d.Kind)
+	}
+	assert.Equal(t, []InventoryDiscrepancyKind{
+		InventoryQuantityMismatch,
+		InventoryLocationMismatch,
+		InventoryUnitMismatch,
+		InventoryExtra,
+		InventoryMissing,
+		InventoryOnHold,
+		InventoryExtra,
+	}, kinds)
+	assert.Equal(t, "PKG-4", r.Discrepancies[3].Label)
+	assert.Equal(t, "PKG-5", r.Discrepancies[4].Label)
+	assert.Equal(t, "PKG-7", r.Discrepancies[6].Label)
+
+	assert.Equal(t, []PackageAdjust{{
+		Label:            "PKG-2",
+		Quantity:         -2,
+		UnitOfMeasure:    "Grams",
+		AdjustmentReason: "Inventory Count",
+		AdjustmentDate:   "2026-10-19",
+	}}, r.Adjusts)
+	assert.Equal(t, []PackageLocation{{Label: "PKG-2", Location: "Back Room", MoveDate: "2026-10-19"}}, r.Locations)
+
+	preview := r.Preview()
+	assert.Contains(t, preview, "would adjust PKG-2 by -2 Grams (Inventory Count)")
+	assert.Contains(t, preview, "would move PKG-2 to Back Room")
+	assert.Empty(t, fc.bodies)
+
+	assert.NoError(t, rc.Apply(r))
+	var adjusts []PackageAdjust
+	assert.NoError(t, json.Unmarshal(fc.bodies["packages/v1/adjust?licenseNumber="+licenseNumber], &adjusts))
+	assert.Equal(t, r.Adjusts, adjusts)
+	var locations []PackageLocation
+	assert.NoError(t, json.Unmarshal(fc.bodies["packages/v1/change/locations?licenseNumber="+licenseNumber], &locations))
+	assert.Equal(t, r.Locations, locations)
+}
@@ -0,0 +1,218 @@
+package metrc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ItemProblem is a missing or invalid field of an `ItemPost`.
+type ItemProblem struct {
+	Index   int
+	Name    string
+	Field   string
+	Message string
+}
+
+func (p ItemProblem) String() string {
+	return fmt.Sprintf("item %d (%s): %s %s", p.Index, p.Name, p.Field, p.Message)
+}
+
+// ItemValidationError lists every problem found in a batch of items.
+type ItemValidationError struct {
+	Problems []ItemProblem
+}
+
+func (e *ItemValidationError) Error() string {
+	var ps []string
+	for _, p := range e.Problems {
+		ps = append(ps, p.String())
+	}
+	return fmt.Sprintf("invalid items: %s", strings.Join(ps, "; "))
+}
+
+// ItemValidator checks items against the requirements of their `ItemCategory` before they are created or updated.
+// Categories and units of measure are fetched from Metrc the first time they are needed and kept until `Reset`.
+type ItemValidator struct {
+	Metrc         *Metrc
+	LicenseNumber *string
+
+	mu         sync.Mutex
+	categories map[string]ItemCategory
+	units      map[string]UnitsOfMeasure
+}
+
+// MakeItemValidator creates a validator for the item categories available to a license.
+func MakeItemValidator(m *Metrc, licenseNumber *string) *ItemValidator {
+	return &ItemValidator{
+		Metrc:         m,
+		LicenseNumber: licenseNumber,
+	}
+}
+
+// Reset forgets the cached categories and units, so they are fetched again on the next validation.
+func (v *ItemValidator) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.categories = nil
+	v.units = nil
+}
+
+// load fetches the categories and units of measure if they are not cached. Must be called with v.mu held.
+func (v *ItemValidator) load() error {
+	if v.categories == nil {
+		cs, err := v.Metrc.GetItemsCategories(v.LicenseNumber)
+		if err != nil {
+			return fmt.Errorf("could not get item categories: %s", err)
+		}
+
+		v.categories = map[string]ItemCategory{}
+		for _, c := range cs {
+			v.categories[c.Name] = c
+		}
+	}
+
+	if v.units == nil {
+		us, err := v.Metrc.GetUnitsOfMeasure()
+		if err != nil {
+			return fmt.Errorf("could not get units of measure: %s", err)
+		}
+
+		v.units = map[string]UnitsOfMeasure{}
+		for _, u := range us {
+			v.units[u.Name] = u
+			v.units[u.Abbreviation] = u
+		}
+	}
+
+	return nil
+}
+
+// itemRequirement is a field that an `ItemCategory` can require.
+type itemRequirement struct {
+	field    string
+	required func(c ItemCategory) bool
+	present  func(i ItemPost) bool
+}
+
+var itemRequirements = []itemRequirement{
+	{"Strain", func(c ItemCategory) bool { return c.RequiresStrain }, func(i ItemPost) bool { return i.Strain != "" }},
+	{"ItemBrand", func(c ItemCategory) bool { return c.RequiresItemBrand }, func(i ItemPost) bool { return i.ItemBrand != "" }},
+	{"AdministrationMethod", func(c ItemCategory) bool { return c.RequiresAdministrationMethod }, func(i ItemPost) bool { return i.AdministrationMethod != "" }},
+	{"UnitCbdPercent", func(c ItemCategory) bool { return c.RequiresUnitCbdPercent }, func(i ItemPost) bool { return i.UnitCbdPercent > 0 }},
+	{"UnitCbdContent", func(c ItemCategory) bool { return c.RequiresUnitCbdContent }, func(i ItemPost) bool { return i.UnitCbdContent > 0 }},
+	{"UnitCbdContentDose", func(c ItemCategory) bool { return c.RequiresUnitCbdContentDose }, func(i ItemPost) bool { return i.UnitCbdContentDose > 0 }},
+	{"UnitThcPercent", func(c ItemCategory) bool { return c.RequiresUnitThcPercent }, func(i ItemPost) bool { return i.UnitThcPercent > 0 }},
+	{"UnitThcContent", func(c ItemCategory) bool { return c.RequiresUnitThcContent }, func(i ItemPost) bool { return i.UnitThcContent > 0 }},
+	{"UnitThcContentUnitOfMeasure", func(c ItemCategory) bool { return c.RequiresUnitThcContent }, func(i ItemPost) bool { return i.UnitThcContentUnitOfMeasure != "" }},
+	{"UnitThcContentDose", func(c ItemCategory) bool { return c.RequiresUnitThcContentDose }, func(i ItemPost) bool { return i.UnitThcContentDose > 0 }},
+	{"UnitThcContentDoseUnitOfMeasure", func(c ItemCategory) bool { return c.RequiresUnitThcContentDose }, func(i ItemPost) bool { return i.UnitThcContentDoseUnitOfMeasure != "" }},
+	{"UnitVolume", func(c ItemCategory) bool { return c.RequiresUnitVolume }, func(i ItemPost) bool { return i.UnitVolume > 0 }},
+	{"UnitVolumeUnitOfMeasure", func(c ItemCategory) bool { return c.RequiresUnitVolume }, func(i ItemPost) bool { return i.UnitVolumeUnitOfMeasure != "" }},
+	{"UnitWeight", func(c ItemCategory) bool { return c.RequiresUnitWeight }, func(i ItemPost) bool { return i.UnitWeight > 0 }},
+	{"UnitWeightUnitOfMeasure", func(c ItemCategory) bool { return c.RequiresUnitWeight }, func(i ItemPost) bool { return i.UnitWeightUnitOfMeasure != "" }},
+	{"ServingSize", func(c ItemCategory) bool { return c.RequiresServingSize }, func(i ItemPost) bool { return i.ServingSize > 0 }},
+	{"SupplyDurationDays", func(c ItemCategory) bool { return c.RequiresSupplyDurationDays }, func(i ItemPost) bool { return i.SupplyDurationDays > 0 }},
+	{"NumberOfDoses", func(c ItemCategory) bool { return c.RequiresNumberOfDoses }, func(i ItemPost) bool { return i.NumberOfDoses > 0 }},
+	{"Ingredients", func(c ItemCategory) bool { return c.RequiresIngredients }, func(i ItemPost) bool { return i.Ingredients != "" }},
+	{"Description", func(c ItemCategory) bool { return c.RequiresDescription }, func(i ItemPost) bool { return i.Description != "" }},
+}
+
+// Validate checks every item and returns an `*ItemValidationError` listing all of their problems, or nil if there are none.
+// An error of any other type means the categories or units of measure could not be fetched.
+func (v *ItemValidator) Validate(items []ItemPost) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	err := v.load()
+	if err != nil {
+		return err
+	}
+
+	var problems []ItemProblem
+	for idx, item := range items {
+		problems = append(problems, v.validateItem(idx, item)...)
+	}
+
+	if len(problems) > 0 {
+		return &ItemValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateItem checks one item. Must be called with v.mu held.
+func (v *ItemValidator) validateItem(idx int, item ItemPost) []ItemProblem {
+	var problems []ItemProblem
+	add := func(field string, format string, args ...interface{}) {
+		problems = append(problems, ItemProblem{
+			Index:   idx,
+			Name:    item.Name,
+			Field:   field,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if item.Name == "" {
+		add("Name", "is required")
+	}
+
+	c, ok := v.categories[item.ItemCategory]
+	if !ok {
+		add("ItemCategory", "%q is not a known item category", item.ItemCategory)
+		return problems
+	}
+
+	for _, r := range itemRequirements {
+		if r.required(c) && !r.present(item) {
+			add(r.field, "is required by category %s", c.Name)
+		}
+	}
+
+	photos := []struct {
+		field    string
+		required int
+		ids      []int
+	}{
+		{"ProductPhotoFileIds", c.RequiresProductPhotos, item.ProductPhotoFileIds},
+		{"LabelPhotoFileIds", c.RequiresLabelPhotos, item.LabelPhotoFileIds},
+		{"PackagingPhotoFileIds", c.RequiresPackagingPhotos, item.PackagingPhotoFileIds},
+	}
+	for _, p := range photos {
+		if len(p.ids) < p.required {
+			add(p.field, "needs %d photos for category %s, got %d", p.required, c.Name, len(p.ids))
+		}
+	}
+
+	// The item's unit must match the category's quantity type, and content units must be of their own kind.
+	units := []struct {
+		field        string
+		unit         string
+		quantityType string
+	}{
+		{"UnitOfMeasure", item.UnitOfMeasure, c.QuantityType},
+		{"UnitThcContentUnitOfMeasure", item.UnitThcContentUnitOfMeasure, "WeightBased"},
+		{"UnitThcContentDoseUnitOfMeasure", item.UnitThcContentDoseUnitOfMeasure, "WeightBased"},
+		{"UnitVolumeUnitOfMeasure", item.UnitVolumeUnitOfMeasure, "VolumeBased"},
+		{"UnitWeightUnitOfMeasure", item.UnitWeightUnitOfMeasure, "WeightBased"},
+	}
+	for _, u := range units {
+		if u.unit == "" {
+			if u.field == "UnitOfMeasure" {
+				add(u.field, "is required")
+			}
+			continue
+		}
+
+		uom, ok := v.units[u.unit]
+		if !ok {
+			add(u.field, "%q is not a known unit of measure", u.unit)
+			continue
+		}
+		if u.quantityType != "" && uom.QuantityType != u.quantityType {
+			add(u.field, "%q is %s but must be %s", u.unit, uom.QuantityType, u.quantityType)
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,57 @@
+package metrc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemValidator(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"items/v1/categories": `[
+			{"Name": "Flower", "QuantityType": "WeightBased", "RequiresStrain": true},
+			{"Name": "Edible", "QuantityType": "CountBased", "RequiresUnitThcContent": true, "RequiresUnitWeight": true, "RequiresIngredients": true, "RequiresProductPhotos": 1}
+		]`,
+		"unitsofmeasure/v1/active": `[
+			{"QuantityType": "CountBased", "Name": "Each", "Abbreviation": "ea"},
+			{"QuantityType": "WeightBased", "Name": "Grams", "Abbreviation": "g"},
+			{"QuantityType": "WeightBased", "Name": "Milligrams", "Abbreviation": "mg"},
+			{"QuantityType": "VolumeBased", "Name": "Milliliters", "Abbreviation": "ml"}
+		]`,
+	})
+	v := MakeItemValidator(&Metrc{Client: fc}, nil)
+
+	err := v.Validate([]ItemPost{
+		{Name: "Flower 1g", ItemCategory: "Flower", UnitOfMeasure: "Grams", Strain: "Kush"},
+		{Name: "Gummy", ItemCategory: "Edible", UnitOfMeasure: "Each", UnitThcContent: 10, UnitThcContentUnitOfMeasure: "mg", UnitWeight: 5, UnitWeightUnitOfMeasure: "g", Ingredients: "Sugar", ProductPhotoFileIds: []int{1}},
+	})
+	assert.NoError(t, err)
+
+	err = v.Validate([]ItemPost{
+		{Name: "Flower 1g", ItemCategory: "Flower", UnitOfMeasure: "Each"},
+		{Name: "Gummy", ItemCategory: "Edible", UnitOfMeasure: "Each", UnitWeight: 5, UnitWeightUnitOfMeasure: "ml"},
+		{Name: "Mystery", ItemCategory: "Unknown"},
+	})
+	var verr *ItemValidationError
+	assert.True(t, errors.As(err, &verr))
+
+	var fields []string
+	for _, p := range verr.Problems {
+		fields = append(fields, fmt.Sprintf("%d:%s", p.Index, p.Field))
+	}
+	assert.ElementsMatch(t, []string{
+		"0:Strain",
+		"0:UnitOfMeasure",
+		"1:UnitThcContent",
+		"1:UnitThcContentUnitOfMeasure",
+		"1:Ingredients",
+		"1:ProductPhotoFileIds",
+		"1:UnitWeightUnitOfMeasure",
+		"2:ItemCategory",
+	}, fields)
+
+	// Categories and units are fetched once.
+	assert.Len(t, fc.gets, 2)
+}
@@ -1,8 +1,15 @@
 package metrc
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
 // TODO: Check null fields in documentation across all structs.
@@ -75,6 +82,9 @@ type ItemPost struct {
 	NumberOfDoses                   int     `json:"NumberOfDoses"`
 	Ingredients                     string  `json:"Ingredients"`
 	Description                     string  `json:"Description"`
+	ProductPhotoFileIds             []int   `json:"ProductPhotoFileIds,omitempty"`
+	LabelPhotoFileIds               []int   `json:"LabelPhotoFileIds,omitempty"`
+	PackagingPhotoFileIds           []int   `json:"PackagingPhotoFileIds,omitempty"`
 }
 
 // ItemCategory represents an Item Category in Metrc.
@@ -107,10 +117,13 @@ type ItemCategory struct {
 }
 
 // ItemBrand represents an Item Brand in Metrc.
+// The brands endpoint returns only the Id, Name, and Status of each brand, so `Id` is the only field missing from the
+// original type. `ItemBrand.Id` is required when updating a brand, and `ItemBrand.Status` is only set by Metrc.
 // See: https://api-ca.metrc.com/Documentation/#Items.get_items_v1_brands.GET
 type ItemBrand struct {
+	Id     int    `json:"Id,omitempty"`
 	Name   string `json:"Name"`
-	Status string `json:"Status"`
+	Status string `json:"Status,omitempty"`
 }
 
 // GetItemsById gets items with an ID.
@@ -201,6 +214,55 @@ func (m *Metrc) GetItemsBrands(licenseNumber string) ([]ItemBrand, error) {
 	return ib, nil
 }
 
+// PostItemsBrandsCreate creates new Item Brands.
+// See: https://api-ca.metrc.com/Documentation/#Items.post_items_v1_brand_create.POST
+func (m *Metrc) PostItemsBrandsCreate(brands []ItemBrand, licenseNumber string) ([]byte, error) {
+	endpoint := "items/v1/brand/create"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(brands)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal item brands: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed posting item brands: %s", err)
+	}
+	return resp, nil
+}
+
+// PostItemsBrandsUpdate updates existing Item Brands. Note that for this endpoint, `ItemBrand.Id` is required in each `ItemBrand` in the input slice.
+// See: https://api-ca.metrc.com/Documentation/#Items.post_items_v1_brand_update.POST
+func (m *Metrc) PostItemsBrandsUpdate(brands []ItemBrand, licenseNumber string) ([]byte, error) {
+	endpoint := "items/v1/brand/update"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(brands)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal item brands: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed posting item brands: %s", err)
+	}
+	return resp, nil
+}
+
+// DeleteItemsBrandById archives an existing Item Brand by ID.
+// See: https://api-ca.metrc.com/Documentation/#Items.delete_items_v1_brand_{id}.DELETE
+func (m *Metrc) DeleteItemsBrandById(id int, licenseNumber string) ([]byte, error) {
+	endpoint := fmt.Sprintf("items/v1/brand/%d", id)
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	resp, err := m.Client.Delete(endpoint)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed deleting item brand %d: %s", id, err)
+	}
+	return resp, nil
+}
+
 // PostItemsCreate creates new Items.
 // See: https://api-ca.metrc.com/Documentation/#Items.post_items_v1_create
 func (m *Metrc) PostItemsCreate(items []ItemPost, licenseNumber *string) ([]byte, error) {
@@ -257,3 +319,134 @@ func (m *Metrc) DeleteItemById(id int, licenseNumber *string) ([]byte, error) {
 	}
 	return resp, nil
 }
+
+// ItemFileUploadResponse represents the IDs Metrc returns after uploading an item photo or document.
+// Reference the IDs in `ItemPost.ProductPhotoFileIds`, `ItemPost.LabelPhotoFileIds`, or `ItemPost.PackagingPhotoFileIds`.
+// See: https://api-ca.metrc.com/Documentation/#Items.post_items_v1_photo
+type ItemFileUploadResponse struct {
+	Ids []int `json:"Ids"`
+}
+
+// itemPhotoMimeTypes are the MIME types Metrc accepts for item photos.
+var itemPhotoMimeTypes = []string{"image/jpeg", "image/png"}
+
+// itemFileMimeTypes are the MIME types Metrc accepts for item documents.
+var itemFileMimeTypes = []string{"image/jpeg", "image/png", "application/pdf"}
+
+// PostItemsPhoto uploads an item photo read from r and returns the file IDs to reference in `ItemPost`.
+// See: https://api-ca.metrc.com/Documentation/#Items.post_items_v1_photo
+func (m *Metrc) PostItemsPhoto(r io.Reader, fileName string, licenseNumber string) ([]int, error) {
+	return m.postItemsUpload("items/v1/photo", r, fileName, itemPhotoMimeTypes, licenseNumber)
+}
+
+// PostItemsPhotoFromPath uploads the item photo at path.
+// See: https://api-ca.metrc.com/Documentation/#Items.post_items_v1_photo
+func (m *Metrc) PostItemsPhotoFromPath(path string, licenseNumber string) ([]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return []int{}, fmt.Errorf("could not open item photo %s: %s", path, err)
+	}
+	defer f.Close()
+
+	return m.PostItemsPhoto(f, filepath.Base(path), licenseNumber)
+}
+
+// PostItemsFile uploads an item document read from r and returns the file IDs to reference in `ItemPost`.
+// See: https://api-ca.metrc.com/Documentation/#Items.post_items_v1_file
+func (m *Metrc) PostItemsFile(r io.Reader, fileName string, licenseNumber string) ([]int, error) {
+	return m.postItemsUpload("items/v1/file", r, fileName, itemFileMimeTypes, licenseNumber)
+}
+
+// PostItemsFileFromPath uploads the item document at path.
+// See: https://api-ca.metrc.com/Documentation/#Items.post_items_v1_file
+func (m *Metrc) PostItemsFileFromPath(path string, licenseNumber string) ([]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return []int{}, fmt.Errorf("could not open item file %s: %s", path, err)
+	}
+	defer f.Close()
+
+	return m.PostItemsFile(f, filepath.Base(path), licenseNumber)
+}
+
+// helper method to encode and post an item upload.
+func (m *Metrc) postItemsUpload(endpoint string, r io.Reader, fileName string, allowed []string, licenseNumber string) ([]int, error) {
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := encodeItemUpload(r, fileName, allowed)
+	if err != nil {
+		return []int{}, fmt.Errorf("could not encode item upload %s: %s", fileName, err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []int{}, fmt.Errorf("failed posting item upload %s: %s", fileName, err)
+	}
+
+	var ur ItemFileUploadResponse
+	err = json.Unmarshal(resp, &ur)
+	if err != nil {
+		return []int{}, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return ur.Ids, nil
+}
+
+// encodeItemUpload builds the JSON request body for an item upload.
+// The MIME type is sniffed from the first bytes of r, and the contents are base64 encoded straight into the body
+// so the file is never held in memory twice.
+func encodeItemUpload(r io.Reader, fileName string, allowed []string) ([]byte, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return []byte{}, fmt.Errorf("could not read file: %s", err)
+	}
+	head = head[:n]
+	if n == 0 {
+		return []byte{}, fmt.Errorf("file is empty")
+	}
+
+	mimeType := http.DetectContentType(head)
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	permitted := false
+	for _, a := range allowed {
+		if a == mimeType {
+			permitted = true
+			break
+		}
+	}
+	if !permitted {
+		return []byte{}, fmt.Errorf("unsupported file type %s, want one of %s", mimeType, strings.Join(allowed, ", "))
+	}
+
+	name, err := json.Marshal(fileName)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal file name: %s", err)
+	}
+	typ, err := json.Marshal(mimeType)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal file type: %s", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`[{"FileName":`)
+	buf.Write(name)
+	buf.WriteString(`,"FileType":`)
+	buf.Write(typ)
+	buf.WriteString(`,"EncodedFileBase64":"`)
+
+	enc := base64.NewEncoder(base64.StdEncoding, &buf)
+	_, err = io.Copy(enc, io.MultiReader(bytes.NewReader(head), r))
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not encode file: %s", err)
+	}
+	err = enc.Close()
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not encode file: %s", err)
+	}
+	buf.WriteString(`"}]`)
+
+	return buf.Bytes(), nil
+}
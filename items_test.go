@@ -1,8 +1,12 @@
 package metrc
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
@@ -102,3 +106,55 @@ func TestItemsCreateUpdateDelete_Integration(t *testing.T) {
 	_, err = m.DeleteItemById(itemId, &licenseNumber)
 	assert.NoError(t, err)
 }
+
+func TestItemsEncodeUpload(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0x01}, 1024)...)
+	body, err := encodeItemUpload(bytes.NewReader(png), "label.png", itemPhotoMimeTypes)
+	assert.NoError(t, err)
+
+	var uploads []struct {
+		FileName          string
+		FileType          string
+		EncodedFileBase64 string
+	}
+	assert.NoError(t, json.Unmarshal(body, &uploads))
+	assert.Equal(t, 1, len(uploads))
+	assert.Equal(t, "label.png", uploads[0].FileName)
+	assert.Equal(t, "image/png", uploads[0].FileType)
+
+	decoded, err := base64.StdEncoding.DecodeString(uploads[0].EncodedFileBase64)
+	assert.NoError(t, err)
+	assert.Equal(t, png, decoded)
+
+	_, err = encodeItemUpload(strings.NewReader("plain text"), "notes.txt", itemPhotoMimeTypes)
+	assert.Error(t, err)
+}
+
+// Tests Create, Update, and Delete for Item Brands.
+func TestItemsBrandsCreateUpdateDelete_Integration(t *testing.T) {
+	// Generate a random name for a new Brand.
+	rand.Seed(time.Now().Unix())
+	name := fmt.Sprintf("%d", rand.Int())
+
+	_, err := m.PostItemsBrandsCreate([]ItemBrand{{Name: name}}, licenseNumber)
+	assert.NoError(t, err)
+
+	// Get all Brands, and then find the Id of the new Brand.
+	brands, err := m.GetItemsBrands(licenseNumber)
+	assert.NoError(t, err)
+	var brandId int
+	for _, b := range brands {
+		if b.Name == name {
+			brandId = b.Id
+			break
+		}
+	}
+
+	// Rename the Brand using update.
+	_, err = m.PostItemsBrandsUpdate([]ItemBrand{{Id: brandId, Name: fmt.Sprintf("%d", rand.Int())}}, licenseNumber)
+	assert.NoError(t, err)
+
+	// Archive the Brand using the ID.
+	_, err = m.DeleteItemsBrandById(brandId, licenseNumber)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,390 @@
+package metrc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// listIterator decodes a list endpoint one item at a time.
+// For v2 licenses it requests one page at a time using pageNumber and pageSize. v1 endpoints are not paged, so a v1
+// license receives the whole list in a single response.
+// When the client implements `StreamClientInterface`, as `HttpClient` does, items are decoded as the response is read,
+// so memory is bounded by the largest item rather than the size of the license for either version. Other clients read
+// each response whole with `Get`, and then only v2 paging bounds memory.
+type listIterator struct {
+	m             *Metrc
+	resource      string
+	path          string
+	licenseNumber string
+	params        url.Values
+	pageSize      int
+
+	pageNumber int
+	totalPages int
+	pageItems  int
+	fetched    bool
+	done       bool
+	dec        *json.Decoder
+	body       io.Closer
+	inData     bool
+	err        error
+}
+
+func (m *Metrc) makeListIterator(resource string, path string, licenseNumber string, params url.Values) listIterator {
+	return listIterator{
+		m:             m,
+		resource:      resource,
+		path:          path,
+		licenseNumber: licenseNumber,
+		params:        params,
+		pageSize:      v2PageSize,
+	}
+}
+
+// next decodes the next item into v, fetching the next page when the current one is exhausted.
+func (it *listIterator) next(v interface{}) bool {
+	for {
+		if it.err != nil || it.done {
+			it.close()
+			return false
+		}
+
+		if it.dec == nil {
+			if !it.morePages() {
+				it.done = true
+				return false
+			}
+			it.err = it.fetch()
+			continue
+		}
+
+		if it.inData && it.dec.More() {
+			err := it.dec.Decode(v)
+			if err != nil {
+				it.err = fmt.Errorf("could not decode item: %s", err)
+				it.close()
+				return false
+			}
+			it.pageItems++
+			return true
+		}
+
+		it.err = it.finishPage()
+	}
+}
+
+// morePages reports whether another page should be requested.
+func (it *listIterator) morePages() bool {
+	if !it.fetched {
+		return true
+	}
+	if it.m.listVersionFor(it.licenseNumber) == APIVersionV1 || it.pageItems == 0 {
+		return false
+	}
+	return it.pageNumber < it.totalPages
+}
+
+// fetch requests the next page and positions the decoder at its first item.
+func (it *listIterator) fetch() error {
+	it.fetched = true
+	it.pageNumber++
+	it.pageItems = 0
+
+	params := url.Values{}
+	for k, vs := range it.params {
+		params[k] = vs
+	}
+	isV1 := it.m.listVersionFor(it.licenseNumber) == APIVersionV1
+	if !isV1 {
+		params.Set("pageNumber", fmt.Sprint(it.pageNumber))
+		params.Set("pageSize", fmt.Sprint(it.pageSize))
+	}
+	endpoint := it.m.versionedEndpoint(it.resource, it.path, it.licenseNumber, params)
+
+	err := it.open(endpoint)
+	if err != nil {
+		return err
+	}
+
+	if isV1 {
+		err = expectDelim(it.dec, '[')
+		if err != nil {
+			return err
+		}
+		it.inData = true
+		return nil
+	}
+
+	// Read the v2 envelope up to the start of its Data array.
+	err = expectDelim(it.dec, '{')
+	if err != nil {
+		return err
+	}
+	for it.dec.More() {
+		isData, err := it.readEnvelopeField()
+		if err != nil {
+			return err
+		}
+		if isData {
+			it.inData = true
+			return nil
+		}
+	}
+	return it.finishPage()
+}
+
+// open requests an endpoint and points the decoder at its response, streaming it when the client can.
+func (it *listIterator) open(endpoint string) error {
+	if sc, ok := it.m.Client.(StreamClientInterface); ok {
+		body, err := sc.GetStream(endpoint)
+		if err != nil {
+			return fmt.Errorf("could not get %s: %s", endpoint, err)
+		}
+		it.body = body
+		it.dec = json.NewDecoder(body)
+		return nil
+	}
+
+	responseBody, err := it.m.Client.Get(endpoint)
+	if err != nil {
+		return fmt.Errorf("could not get %s: %s", endpoint, err)
+	}
+	it.dec = json.NewDecoder(bytes.NewReader(responseBody))
+	return nil
+}
+
+// finishPage reads the rest of the current page, including any envelope fields after the Data array.
+func (it *listIterator) finishPage() error {
+	if it.inData {
+		err := expectDelim(it.dec, ']')
+		if err != nil {
+			return err
+		}
+		it.inData = false
+	}
+
+	if it.m.listVersionFor(it.licenseNumber) != APIVersionV1 {
+		for it.dec.More() {
+			_, err := it.readEnvelopeField()
+			if err != nil {
+				return err
+			}
+		}
+		err := expectDelim(it.dec, '}')
+		if err != nil {
+			return err
+		}
+	}
+
+	it.close()
+	return nil
+}
+
+// close closes the response being read, if any.
+func (it *listIterator) close() {
+	if it.body != nil {
+		it.body.Close()
+		it.body = nil
+	}
+	it.dec = nil
+}
+
+// readEnvelopeField reads one field of a v2 envelope. For the Data field, only the opening bracket is read.
+func (it *listIterator) readEnvelopeField() (bool, error) {
+	tok, err := it.dec.Token()
+	if err != nil {
+		return false, fmt.Errorf("could not read page: %s", err)
+	}
+
+	switch tok {
+	case "Data":
+		return true, expectDelim(it.dec, '[')
+	case "TotalPages":
+		err = it.dec.Decode(&it.totalPages)
+	default:
+		var skip json.RawMessage
+		err = it.dec.Decode(&skip)
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not read page field %v: %s", tok, err)
+	}
+	return false, nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("could not read response: %s", err)
+	}
+	if tok != want {
+		return fmt.Errorf("expected %v in response, got %v", want, tok)
+	}
+	return nil
+}
+
+// PackageIterator pages through Packages. Call `Next` until it returns false, then check `Err`.
+type PackageIterator struct {
+	it   listIterator
+	item PackageGet
+}
+
+// Next advances to the next Package, returning false when there are no more or an error occurred.
+func (i *PackageIterator) Next() bool {
+	i.item = PackageGet{}
+	return i.it.next(&i.item)
+}
+
+// Item returns the current Package.
+func (i *PackageIterator) Item() PackageGet {
+	return i.item
+}
+
+// Err returns the error that stopped iteration, if any.
+func (i *PackageIterator) Err() error {
+	return i.it.err
+}
+
+// Close stops iteration early, releasing the response being read. It is not needed once `Next` has returned false.
+func (i *PackageIterator) Close() {
+	i.it.done = true
+	i.it.close()
+}
+
+// IteratePackagesActive iterates over the active Packages for a license number. Optional timestamps can be passed for filtering.
+// See: https://api-ca.metrc.com/Documentation/#Packages.get_packages_v1_active
+func (m *Metrc) IteratePackagesActive(licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) *PackageIterator {
+	return &PackageIterator{it: m.makeListIterator("packages", "active", licenseNumber, lastModifiedParams(lastModifiedStart, lastModifiedEnd))}
+}
+
+// IteratePackagesOnHold iterates over the on hold Packages for a license number. Optional timestamps can be passed for filtering.
+// See: https://api-ca.metrc.com/Documentation/#Packages.get_packages_v1_onhold
+func (m *Metrc) IteratePackagesOnHold(licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) *PackageIterator {
+	return &PackageIterator{it: m.makeListIterator("packages", "onhold", licenseNumber, lastModifiedParams(lastModifiedStart, lastModifiedEnd))}
+}
+
+// IteratePackagesInactive iterates over the inactive Packages for a license number. Optional timestamps can be passed for filtering.
+// See: https://api-ca.metrc.com/Documentation/#Packages.get_packages_v1_inactive
+func (m *Metrc) IteratePackagesInactive(licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) *PackageIterator {
+	return &PackageIterator{it: m.makeListIterator("packages", "inactive", licenseNumber, lastModifiedParams(lastModifiedStart, lastModifiedEnd))}
+}
+
+// PlantIterator pages through Plants. Call `Next` until it returns false, then check `Err`.
+type PlantIterator struct {
+	it   listIterator
+	item Plant
+}
+
+// Next advances to the next Plant, returning false when there are no more or an error occurred.
+func (i *PlantIterator) Next() bool {
+	i.item = Plant{}
+	return i.it.next(&i.item)
+}
+
+// Item returns the current Plant.
+func (i *PlantIterator) Item() Plant {
+	return i.item
+}
+
+// Err returns the error that stopped iteration, if any.
+func (i *PlantIterator) Err() error {
+	return i.it.err
+}
+
+// Close stops iteration early, releasing the response being read. It is not needed once `Next` has returned false.
+func (i *PlantIterator) Close() {
+	i.it.done = true
+	i.it.close()
+}
+
+// IteratePlantsVegetative iterates over the vegetative Plants for a license number.
+// See: https://api-ca.metrc.com/Documentation/#Plants.get_plants_v1_vegetative
+func (m *Metrc) IteratePlantsVegetative(licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) *PlantIterator {
+	return &PlantIterator{it: m.makeListIterator("plants", "vegetative", licenseNumber, lastModifiedParams(lastModifiedStart, lastModifiedEnd))}
+}
+
+// IteratePlantsFlowering iterates over the flowering Plants for a license number.
+// See: https://api-ca.metrc.com/Documentation/#Plants.get_plants_v1_flowering
+func (m *Metrc) IteratePlantsFlowering(licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) *PlantIterator {
+	return &PlantIterator{it: m.makeListIterator("plants", "flowering", licenseNumber, lastModifiedParams(lastModifiedStart, lastModifiedEnd))}
+}
+
+// IteratePlantsOnHold iterates over the on hold Plants for a license number.
+// See: https://api-ca.metrc.com/Documentation/#Plants.get_plants_v1_onhold
+func (m *Metrc) IteratePlantsOnHold(licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) *PlantIterator {
+	return &PlantIterator{it: m.makeListIterator("plants", "onhold", licenseNumber, lastModifiedParams(lastModifiedStart, lastModifiedEnd))}
+}
+
+// IteratePlantsInactive iterates over the inactive Plants for a license number.
+// See: https://api-ca.metrc.com/Documentation/#Plants.get_plants_v1_inactive
+func (m *Metrc) IteratePlantsInactive(licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) *PlantIterator {
+	return &PlantIterator{it: m.makeListIterator("plants", "inactive", licenseNumber, lastModifiedParams(lastModifiedStart, lastModifiedEnd))}
+}
+
+// SalesReceiptIterator pages through sales receipts. Call `Next` until it returns false, then check `Err`.
+type SalesReceiptIterator struct {
+	it   listIterator
+	item SalesReceiptGet
+}
+
+// Next advances to the next receipt, returning false when there are no more or an error occurred.
+func (i *SalesReceiptIterator) Next() bool {
+	i.item = SalesReceiptGet{}
+	return i.it.next(&i.item)
+}
+
+// Item returns the current receipt.
+func (i *SalesReceiptIterator) Item() SalesReceiptGet {
+	return i.item
+}
+
+// Err returns the error that stopped iteration, if any.
+func (i *SalesReceiptIterator) Err() error {
+	return i.it.err
+}
+
+// Close stops iteration early, releasing the response being read. It is not needed once `Next` has returned false.
+func (i *SalesReceiptIterator) Close() {
+	i.it.done = true
+	i.it.close()
+}
+
+// salesReceiptParams returns the optional filters for receipt list endpoints.
+// Metrc API specification says that sales date range and last modified range cannot both be specified.
+func salesReceiptParams(salesDateStart *string, salesDateEnd *string, lastModifiedStart *string, lastModifiedEnd *string) (url.Values, error) {
+	hasSalesDate := (salesDateStart != nil || salesDateEnd != nil)
+	hasLastModified := (lastModifiedStart != nil || lastModifiedEnd != nil)
+	if hasSalesDate && hasLastModified {
+		return nil, fmt.Errorf("cannot specify both salesDate and lastModified params")
+	}
+
+	params := lastModifiedParams(lastModifiedStart, lastModifiedEnd)
+	if salesDateStart != nil {
+		params.Set("salesDateStart", *salesDateStart)
+	}
+	if salesDateEnd != nil {
+		params.Set("salesDateEnd", *salesDateEnd)
+	}
+	return params, nil
+}
+
+func (m *Metrc) iterateSalesReceipts(status string, licenseNumber string, salesDateStart *string, salesDateEnd *string, lastModifiedStart *string, lastModifiedEnd *string) *SalesReceiptIterator {
+	params, err := salesReceiptParams(salesDateStart, salesDateEnd, lastModifiedStart, lastModifiedEnd)
+	i := &SalesReceiptIterator{it: m.makeListIterator("sales", "receipts/"+status, licenseNumber, params)}
+	i.it.err = err
+	return i
+}
+
+// IterateSalesReceiptsActive iterates over the active receipts for a license number.
+// See: https://api-ca.metrc.com/Documentation/#Sales.get_sales_v1_receipts_active
+func (m *Metrc) IterateSalesReceiptsActive(licenseNumber string, salesDateStart *string, salesDateEnd *string, lastModifiedStart *string, lastModifiedEnd *string) *SalesReceiptIterator {
+	return m.iterateSalesReceipts("active", licenseNumber, salesDateStart, salesDateEnd, lastModifiedStart, lastModifiedEnd)
+}
+
+// IterateSalesReceiptsInactive iterates over the inactive receipts for a license number.
+// See: https://api-ca.metrc.com/Documentation/#Sales.get_sales_v1_receipts_inactive
+func (m *Metrc) IterateSalesReceiptsInactive(licenseNumber string, salesDateStart *string, salesDateEnd *string, lastModifiedStart *string, lastModifiedEnd *string) *SalesReceiptIterator {
+	return m.iterateSalesReceipts("inactive", licenseNumber, salesDateStart, salesDateEnd, lastModifiedStart, lastModifiedEnd)
+}
@@ -0,0 +1,139 @@
+package metrc
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIteratorsPackages(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"packages/v1/inactive": `[{"Id": 1}, {"Id": 2}, {"Id": 3}]`,
+		"packages/v2/inactive?licenseNumber=LIC-2&pageNumber=1&pageSize=20": `{"Total": 3, "Data": [{"Id": 1}, {"Id": 2}], "Page": 1, "TotalPages": 2}`,
+		"packages/v2/inactive?licenseNumber=LIC-2&pageNumber=2&pageSize=20": `{"Data": [{"Id": 3}], "Page": 2, "TotalPages": 2}`,
+	})
+	m := &Metrc{
+		Client:              fc,
+		LicenseListVersions: map[string]APIVersion{"LIC-2": APIVersionV2},
+	}
+
+	for _, license := range []string{"LIC-1", "LIC-2"} {
+		it := m.IteratePackagesInactive(license, nil, nil)
+		var ids []int
+		for it.Next() {
+			ids = append(ids, it.Item().Id)
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []int{1, 2, 3}, ids, license)
+	}
+}
+
+func TestIteratorsErrors(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"plants/v1/inactive": `[{"Id": 1}, {"Id": "bad"}]`,
+	})
+	m := &Metrc{Client: fc}
+
+	it := m.IteratePlantsInactive("LIC-1", nil, nil)
+	assert.True(t, it.Next())
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err())
+
+	start := "2021-06-01"
+	receipts := m.IterateSalesReceiptsInactive("LIC-1", &start, nil, &start, nil)
+	assert.False(t, receipts.Next())
+	assert.Error(t, receipts.Err())
+
+	empty := makeFakeClient(map[string]string{
+		"plants/v2/flowering": `{"Data": [], "TotalPages": 5}`,
+	})
+	m = &Metrc{Client: empty, ListVersion: APIVersionV2}
+	flowering := m.IteratePlantsFlowering("LIC-1", nil, nil)
+	assert.False(t, flowering.Next())
+	assert.NoError(t, flowering.Err())
+	assert.Len(t, empty.gets, 1)
+}
+
+// streamingFakeClient serves fake responses through `GetStream`, counting the bodies still open.
+type streamingFakeClient struct {
+	*fakeClient
+	open int
+}
+
+type fakeBody struct {
+	io.Reader
+	c *streamingFakeClient
+}
+
+func (b fakeBody) Close() error {
+	b.c.open--
+	return nil
+}
+
+func (c *streamingFakeClient) GetStream(endpoint string) (io.ReadCloser, error) {
+	c.gets = append(c.gets, endpoint)
+	resp, err := c.respond(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	c.open++
+	return fakeBody{Reader: strings.NewReader(string(resp)), c: c}, nil
+}
+
+func TestIteratorsStream(t *testing.T) {
+	sc := &streamingFakeClient{fakeClient: makeFakeClient(map[string]string{
+		"packages/v1/active": `[{"Id": 1}, {"Id": 2}, {"Id": 3}]`,
+		"packages/v2/active?licenseNumber=LIC-2&pageNumber=1&pageSize=20": `{"Data": [{"Id": 1}, {"Id": 2}], "TotalPages": 2}`,
+		"packages/v2/active?licenseNumber=LIC-2&pageNumber=2&pageSize=20": `{"Data": [{"Id": 3}], "TotalPages": 2}`,
+	})}
+	m := &Metrc{
+		Client:              sc,
+		LicenseListVersions: map[string]APIVersion{"LIC-2": APIVersionV2},
+	}
+
+	for _, license := range []string{"LIC-1", "LIC-2"} {
+		it := m.IteratePackagesActive(license, nil, nil)
+		var ids []int
+		for it.Next() {
+			ids = append(ids, it.Item().Id)
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []int{1, 2, 3}, ids, license)
+		assert.Equal(t, 0, sc.open, license)
+	}
+
+	// Stopping early closes the response being read.
+	it := m.IteratePackagesActive("LIC-1", nil, nil)
+	assert.True(t, it.Next())
+	assert.Equal(t, 1, sc.open)
+	it.Close()
+	assert.Equal(t, 0, sc.open)
+	assert.False(t, it.Next())
+}
+
+func TestIteratorsStreamErrors(t *testing.T) {
+	sc := &streamingFakeClient{fakeClient: makeFakeClient(map[string]string{
+		"plants/v1/inactive":  `[{"Id": 1}, {"Id": "bad"}]`,
+		"plants/v2/flowering": `{"Data": [{"Id": 1}`,
+		"plants/v2/onhold":    `{"TotalPages": "bad"}`,
+	})}
+	m := &Metrc{Client: sc}
+
+	// A malformed item closes the response.
+	it := m.IteratePlantsInactive("LIC-1", nil, nil)
+	assert.True(t, it.Next())
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err())
+	assert.Equal(t, 0, sc.open)
+
+	// So does a truncated page or a malformed envelope.
+	m.ListVersion = APIVersionV2
+	for _, it := range []*PlantIterator{m.IteratePlantsFlowering("LIC-1", nil, nil), m.IteratePlantsOnHold("LIC-1", nil, nil)} {
+		for it.Next() {
+		}
+		assert.Error(t, it.Err())
+		assert.Equal(t, 0, sc.open)
+	}
+}
@@ -123,6 +123,11 @@ type LabTestRecordResult struct {
 // PostLabTestsRecord records lab tests in Metrc.
 // See: https://testing-api-ca.metrc.com/Documentation#LabTests.post_labtests_v1_record.POST
 func (m *Metrc) PostLabTestsRecord(records []LabTestRecord, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "record lab test results", "CanTestPackages")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "labtests/v1/record"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -190,3 +195,34 @@ func (m *Metrc) PutLabTestsResultsRelease(packages []LabTestResultRelease, licen
 
 	return resp, nil
 }
+
+// LabTestBatch represents a batch of lab test types that can be required of a testing package.
+// See: https://testing-api-ca.metrc.com/Documentation#LabTests.get_labtests_v1_batches.GET
+type LabTestBatch struct {
+	Id                        int    `json:"Id"`
+	Name                      string `json:"Name"`
+	LabTestTypeCount          int    `json:"LabTestTypeCount"`
+	ForProductCategoryType    string `json:"ForProductCategoryType"`
+	RequiresAllFromLabTestIds bool   `json:"RequiresAllFromLabTestIds"`
+	LabTestTypes              []int  `json:"LabTestTypes"`
+}
+
+// GetLabTestsBatches gets the lab test batches that can be required when creating testing packages.
+// See: https://testing-api-ca.metrc.com/Documentation#LabTests.get_labtests_v1_batches.GET
+func (m *Metrc) GetLabTestsBatches(licenseNumber string) ([]LabTestBatch, error) {
+	endpoint := "labtests/v1/batches"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	var ltb []LabTestBatch
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return ltb, fmt.Errorf("could not get lab test batches response: %s", err)
+	}
+
+	err = json.Unmarshal(responseBody, &ltb)
+	if err != nil {
+		return ltb, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return ltb, nil
+}
@@ -46,3 +46,8 @@ func TestLabTestsDocument_Integration(t *testing.T) {
 func TestLabTestsRelease_Integration(t *testing.T) {
 	// TODO: Implement.
 }
+
+func TestLabTestsBatches_Integration(t *testing.T) {
+	_, err := m.GetLabTestsBatches(licenseNumber)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,264 @@
+package metrc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LocationUse is what a Location must be able to hold, matching the "For" flags of `LocationGet`.
+type LocationUse string
+
+// The uses a Location can allow.
+const (
+	LocationForPlants       LocationUse = "plants"
+	LocationForPlantBatches LocationUse = "plant batches"
+	LocationForHarvests     LocationUse = "harvests"
+	LocationForPackages     LocationUse = "packages"
+)
+
+// Allows reports whether the Location can hold objects of the use.
+func (l LocationGet) Allows(use LocationUse) bool {
+	switch use {
+	case LocationForPlants:
+		return l.ForPlants
+	case LocationForPlantBatches:
+		return l.ForPlantBatches
+	case LocationForHarvests:
+		return l.ForHarvests
+	case LocationForPackages:
+		return l.ForPackages
+	}
+	return false
+}
+
+// LocationProblem is a move or create into a Location that is unknown or cannot hold the object.
+// Suggestions lists the active Locations that can hold it.
+type LocationProblem struct {
+	Index       int
+	Location    string
+	Use         LocationUse
+	Message     string
+	Suggestions []string
+}
+
+func (p LocationProblem) String() string {
+	s := fmt.Sprintf("entry %d: location %q %s", p.Index, p.Location, p.Message)
+	if len(p.Suggestions) > 0 {
+		s += fmt.Sprintf(" (try %s)", strings.Join(p.Suggestions, ", "))
+	}
+	return s
+}
+
+// LocationValidationError lists every problem found in a batch of moves or creates.
+type LocationValidationError struct {
+	Problems []LocationProblem
+}
+
+func (e *LocationValidationError) Error() string {
+	var ps []string
+	for _, p := range e.Problems {
+		ps = append(ps, p.String())
+	}
+	return fmt.Sprintf("incompatible locations: %s", strings.Join(ps, "; "))
+}
+
+// LocationValidator checks that moves and creates target Locations that can hold the object, before they are sent to Metrc.
+// Active Locations are fetched from Metrc the first time they are needed and kept until `Reset`.
+type LocationValidator struct {
+	Metrc         *Metrc
+	LicenseNumber string
+
+	mu        sync.Mutex
+	locations map[string]LocationGet
+}
+
+// MakeLocationValidator creates a validator for the active Locations of a license.
+func MakeLocationValidator(m *Metrc, licenseNumber string) *LocationValidator {
+	return &LocationValidator{
+		Metrc:         m,
+		LicenseNumber: licenseNumber,
+	}
+}
+
+// Reset forgets the cached Locations, so they are fetched again on the next check.
+func (v *LocationValidator) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.locations = nil
+}
+
+// load fetches the active Locations if they are not cached. Must be called with v.mu held.
+func (v *LocationValidator) load() error {
+	if v.locations != nil {
+		return nil
+	}
+
+	ls, err := v.Metrc.GetLocationsActive(&v.LicenseNumber)
+	if err != nil {
+		return fmt.Errorf("could not get active locations: %s", err)
+	}
+
+	v.locations = map[string]LocationGet{}
+	for _, l := range ls {
+		v.locations[l.Name] = l
+	}
+	return nil
+}
+
+// Location resolves a Location name to its `LocationGet`.
+func (v *LocationValidator) Location(name string) (LocationGet, bool, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	err := v.load()
+	if err != nil {
+		return LocationGet{}, false, err
+	}
+
+	l, ok := v.locations[name]
+	return l, ok, nil
+}
+
+// Compatible returns the names of the active Locations that can hold objects of the use, in name order.
+func (v *LocationValidator) Compatible(use LocationUse) ([]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	err := v.load()
+	if err != nil {
+		return []string{}, err
+	}
+	return v.compatible(use), nil
+}
+
+// compatible must be called with v.mu held.
+func (v *LocationValidator) compatible(use LocationUse) []string {
+	names := []string{}
+	for name, l := range v.locations {
+		if l.Allows(use) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// check validates the Location names of a batch, in order. Empty names are skipped, as Location is optional on creates.
+func (v *LocationValidator) check(names []string, use LocationUse) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	err := v.load()
+	if err != nil {
+		return err
+	}
+
+	var problems []LocationProblem
+	for idx, name := range names {
+		if name == "" {
+			continue
+		}
+
+		l, ok := v.locations[name]
+		switch {
+		case !ok:
+			problems = append(problems, LocationProblem{Index: idx, Location: name, Use: use, Message: "is not an active location"})
+		case !l.Allows(use):
+			problems = append(problems, LocationProblem{Index: idx, Location: name, Use: use, Message: fmt.Sprintf("of type %s cannot hold %s", l.TypeName, use)})
+		default:
+			continue
+		}
+		problems[len(problems)-1].Suggestions = v.compatible(use)
+	}
+
+	if len(problems) > 0 {
+		return &LocationValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// optionalNames dereferences optional Location names, using "" for nil.
+func optionalNames(names []*string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		if n != nil {
+			out[i] = *n
+		}
+	}
+	return out
+}
+
+// CheckPlantMoves checks the Locations of `PostPlantsMovePlants`.
+func (v *LocationValidator) CheckPlantMoves(moves []PlantMovePost) error {
+	names := make([]string, len(moves))
+	for i, mv := range moves {
+		names[i] = mv.Location
+	}
+	return v.check(names, LocationForPlants)
+}
+
+// CheckHarvestMoves checks the drying Locations of `PutHarvestsMove`.
+func (v *LocationValidator) CheckHarvestMoves(moves []HarvestMove) error {
+	names := make([]string, len(moves))
+	for i, mv := range moves {
+		names[i] = mv.DryingLocation
+	}
+	return v.check(names, LocationForHarvests)
+}
+
+// CheckPackageLocations checks the Locations of `PostPackagesChangeLocations`.
+func (v *LocationValidator) CheckPackageLocations(moves []PackageLocation) error {
+	names := make([]string, len(moves))
+	for i, mv := range moves {
+		names[i] = mv.Location
+	}
+	return v.check(names, LocationForPackages)
+}
+
+// CheckPlantBatchMoves checks the Locations of `PutPlantBatchesMove`.
+func (v *LocationValidator) CheckPlantBatchMoves(moves []PlantBatchMove) error {
+	names := make([]string, len(moves))
+	for i, mv := range moves {
+		names[i] = mv.Location
+	}
+	return v.check(names, LocationForPlantBatches)
+}
+
+// CheckPackagePosts checks the Locations of `PostPackagesCreate` and its variants.
+func (v *LocationValidator) CheckPackagePosts(packages []PackagePost) error {
+	names := make([]*string, len(packages))
+	for i, p := range packages {
+		names[i] = p.Location
+	}
+	return v.check(optionalNames(names), LocationForPackages)
+}
+
+// CheckHarvestPackagePosts checks the Locations of `PostHarvestsCreatePackages`.
+func (v *LocationValidator) CheckHarvestPackagePosts(packages []HarvestPackagePost) error {
+	names := make([]*string, len(packages))
+	for i, p := range packages {
+		names[i] = p.Location
+	}
+	return v.check(optionalNames(names), LocationForPackages)
+}
+
+// CheckPlantBatchPlantings checks the Locations of `PostPlantBatchesCreatePlantings`.
+func (v *LocationValidator) CheckPlantBatchPlantings(batches []PlantBatchPlanting) error {
+	names := make([]*string, len(batches))
+	for i, b := range batches {
+		names[i] = b.Location
+	}
+	return v.check(optionalNames(names), LocationForPlantBatches)
+}
+
+// CheckPlantHarvests checks the drying Locations of `PostPlantsHarvest`.
+func (v *LocationValidator) CheckPlantHarvests(plants []PlantHarvest) error {
+	names := make([]string, len(plants))
+	for i, p := range plants {
+		names[i] = p.DryingLocation
+	}
+	return v.check(names, LocationForHarvests)
+}
@@ -0,0 +1,41 @@
+package metrc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationValidator(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"locations/v1/active": `[
+			{"Name": "Veg Room", "LocationTypeName": "Grow", "ForPlants": true, "ForPlantBatches": true},
+			{"Name": "Flower Room", "LocationTypeName": "Grow", "ForPlants": true},
+			{"Name": "Dry Room", "LocationTypeName": "Drying", "ForHarvests": true},
+			{"Name": "Vault", "LocationTypeName": "Storage", "ForPackages": true}
+		]`,
+	})
+	v := MakeLocationValidator(&Metrc{Client: fc}, licenseNumber)
+
+	assert.NoError(t, v.CheckPlantMoves([]PlantMovePost{{Location: "Veg Room"}, {Location: "Flower Room"}}))
+	vault := "Vault"
+	assert.NoError(t, v.CheckPackagePosts([]PackagePost{{}, {Location: &vault}}))
+
+	err := v.CheckPackageLocations([]PackageLocation{{Location: "Vault"}, {Location: "Dry Room"}, {Location: "Garage"}})
+	var lerr *LocationValidationError
+	assert.True(t, errors.As(err, &lerr))
+	assert.Len(t, lerr.Problems, 2)
+	assert.Equal(t, 1, lerr.Problems[0].Index)
+	assert.Contains(t, lerr.Problems[0].Message, "cannot hold packages")
+	assert.Equal(t, []string{"Vault"}, lerr.Problems[0].Suggestions)
+	assert.Equal(t, "Garage", lerr.Problems[1].Location)
+	assert.Contains(t, lerr.Problems[1].Message, "not an active location")
+
+	compatible, err := v.Compatible(LocationForPlants)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Flower Room", "Veg Room"}, compatible)
+
+	// Locations are fetched once.
+	assert.Len(t, fc.gets, 1)
+}
@@ -4,6 +4,21 @@ package metrc
 // Implements MetrcInterface.
 type Metrc struct {
 	Client ClientInterface
+
+	// ValidateLabels rejects malformed tag labels locally, before a request is sent to Metrc.
+	ValidateLabels bool
+
+	// Capabilities, when set, rejects writes the facility is not permitted to make with `ErrNotPermitted`, before a request is sent to Metrc.
+	Capabilities *CapabilityGuard
+
+	// ListVersion is the API version of the list endpoints for the state, v1 when empty.
+	// LicenseListVersions overrides it per license, so licenses can be migrated one at a time.
+	// Only the paged list endpoints are version aware: the Package, Plant, Harvest, and Plant Batch lists by status, e.g.
+	// `GetPackagesActive`, the active and inactive sales receipt lists, and the `Iterate` methods. Every other endpoint,
+	// including every write and every lookup by id or label, always uses v1. For v2 the paging envelope is unwrapped so the
+	// same types are returned, but item fields are decoded by their v1 names and are not otherwise mapped.
+	ListVersion         APIVersion
+	LicenseListVersions map[string]APIVersion
 }
 
 // MetrcInterface specifies the methods through which an external developer can call the Metrc API.
@@ -15,6 +30,10 @@ type MetrcInterface interface {
 	GetItemsById(id int, licenseNumber *string) (ItemGet, error)
 	GetItemsActive(licenseNumber *string) ([]ItemGet, error)
 	GetItemsCategories(licenseNumber *string) ([]ItemCategory, error)
+	GetItemsBrands(licenseNumber string) ([]ItemBrand, error)
+	PostItemsBrandsCreate(brands []ItemBrand, licenseNumber string) ([]byte, error)
+	PostItemsBrandsUpdate(brands []ItemBrand, licenseNumber string) ([]byte, error)
+	DeleteItemsBrandById(id int, licenseNumber string) ([]byte, error)
 	PostItemsCreate(items []ItemPost, licenseNumber *string) ([]byte, error)
 	PostItemsUpdate(items []ItemPost, licenseNumber *string) ([]byte, error)
 	DeleteItemById(id int, licenseNumber *string) ([]byte, error)
@@ -0,0 +1,159 @@
+package metrc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// PackageAdjustProblem is an adjustment that Metrc would reject.
+type PackageAdjustProblem struct {
+	Index   int
+	Label   string
+	Message string
+}
+
+func (p PackageAdjustProblem) String() string {
+	return fmt.Sprintf("adjustment %d (%s): %s", p.Index, p.Label, p.Message)
+}
+
+// PackageAdjustValidationError lists every problem found in a batch of adjustments.
+type PackageAdjustValidationError struct {
+	Problems []PackageAdjustProblem
+}
+
+func (e *PackageAdjustValidationError) Error() string {
+	var ps []string
+	for _, p := range e.Problems {
+		ps = append(ps, p.String())
+	}
+	return fmt.Sprintf("invalid package adjustments: %s", strings.Join(ps, "; "))
+}
+
+// PackageAdjuster validates and posts Package adjustments for a license.
+// Adjust reasons are fetched from Metrc the first time they are needed and kept until `Reset`.
+type PackageAdjuster struct {
+	Metrc         *Metrc
+	LicenseNumber string
+
+	mu      sync.Mutex
+	reasons map[string]PackageAdjustReasons
+}
+
+// MakePackageAdjuster creates an adjuster for the Packages of a license.
+func MakePackageAdjuster(m *Metrc, licenseNumber string) *PackageAdjuster {
+	return &PackageAdjuster{
+		Metrc:         m,
+		LicenseNumber: licenseNumber,
+	}
+}
+
+// Reset forgets the cached adjust reasons, so they are fetched again on the next adjustment.
+func (a *PackageAdjuster) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.reasons = nil
+}
+
+// reason looks up an adjust reason case insensitively, fetching the reasons if they are not cached.
+func (a *PackageAdjuster) reason(name string) (PackageAdjustReasons, bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.reasons == nil {
+		rs, err := a.Metrc.GetPackagesAdjustReasons(a.LicenseNumber)
+		if err != nil {
+			return PackageAdjustReasons{}, false, fmt.Errorf("could not get adjust reasons: %s", err)
+		}
+
+		a.reasons = map[string]PackageAdjustReasons{}
+		for _, r := range rs {
+			a.reasons[strings.ToLower(r.Name)] = r
+		}
+	}
+
+	r, ok := a.reasons[strings.ToLower(strings.TrimSpace(name))]
+	return r, ok, nil
+}
+
+// Prepare validates adjustments against the license's adjust reasons and the current Packages.
+// Each reason must be one of the license's reasons, with a note if the reason requires one, and no Package may be
+// adjusted below zero, counting every adjustment to the same Package. Adjustments in a different unit than their
+// Package are converted to the Package's unit.
+// The returned adjustments are ready to post. If any adjustment is invalid, a `*PackageAdjustValidationError` lists all of them.
+func (a *PackageAdjuster) Prepare(adjusts []PackageAdjust) ([]PackageAdjust, error) {
+	prepared := make([]PackageAdjust, 0, len(adjusts))
+	packages := map[string]*PackageGet{}
+	var problems []PackageAdjustProblem
+	add := func(idx int, label string, format string, args ...interface{}) {
+		problems = append(problems, PackageAdjustProblem{Index: idx, Label: label, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for idx, adj := range adjusts {
+		r, ok, err := a.reason(adj.AdjustmentReason)
+		if err != nil {
+			return []PackageAdjust{}, err
+		}
+		if !ok {
+			add(idx, adj.Label, "%q is not an adjust reason for license %s", adj.AdjustmentReason, a.LicenseNumber)
+		} else {
+			adj.AdjustmentReason = r.Name
+			if r.RequiresNote && (adj.ReasonNote == nil || strings.TrimSpace(*adj.ReasonNote) == "") {
+				add(idx, adj.Label, "reason %s requires a note", r.Name)
+			}
+		}
+
+		p, ok := packages[adj.Label]
+		if !ok {
+			pkg, err := a.Metrc.GetPackagesByLabel(adj.Label, &a.LicenseNumber)
+			if err != nil {
+				add(idx, adj.Label, "could not get package: %s", err)
+				prepared = append(prepared, adj)
+				continue
+			}
+			p = &pkg
+			packages[adj.Label] = p
+		}
+
+		// Units Metrc names exactly are not converted, so units the converter does not know can still be adjusted.
+		unit := adj.UnitOfMeasure
+		if unit == "" || unit == p.UnitOfMeasureName || unit == p.UnitOfMeasureAbbreviation || SameUnit(unit, p.UnitOfMeasureName) {
+			adj.UnitOfMeasure = p.UnitOfMeasureName
+		} else {
+			converted, err := adj.ConvertTo(p.UnitOfMeasureName)
+			if err != nil {
+				add(idx, adj.Label, "%s", err)
+				prepared = append(prepared, adj)
+				continue
+			}
+			adj.Quantity = converted.Quantity
+			adj.UnitOfMeasure = p.UnitOfMeasureName
+		}
+
+		// Tolerate floating point error from conversions.
+		remaining := p.Quantity + adj.Quantity
+		if remaining < -1e-9 {
+			add(idx, adj.Label, "would leave %g %s of %g %s", remaining, p.UnitOfMeasureName, p.Quantity, p.UnitOfMeasureName)
+		} else {
+			p.Quantity = remaining
+		}
+
+		prepared = append(prepared, adj)
+	}
+
+	if len(problems) > 0 {
+		return prepared, &PackageAdjustValidationError{Problems: problems}
+	}
+	return prepared, nil
+}
+
+// Adjust prepares adjustments with `Prepare` and posts them with `PostPackagesAdjust` if they are all valid.
+func (a *PackageAdjuster) Adjust(adjusts []PackageAdjust) ([]byte, error) {
+	prepared, err := a.Prepare(adjusts)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return a.Metrc.PostPackagesAdjust(prepared, a.LicenseNumber)
+}
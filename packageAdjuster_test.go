@@ -0,0 +1,75 @@
+package metrc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageAdjuster(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"packages/v1/adjust/reasons":           `[{"Name": "Drying", "RequiresNote": false}, {"Name": "Waste", "RequiresNote": true}]`,
+		"packages/v1/1A4FF0100000022000000001": `{"Label": "1A4FF0100000022000000001", "Quantity": 10, "UnitOfMeasureName": "Grams", "UnitOfMeasureAbbreviation": "g"}`,
+		"packages/v1/1A4FF0100000022000000002": `{"Label": "1A4FF0100000022000000002", "Quantity": 3, "UnitOfMeasureName": "Each", "UnitOfMeasureAbbreviation": "ea"}`,
+	})
+	a := MakePackageAdjuster(&Metrc{Client: fc}, licenseNumber)
+
+	note := "Spilled"
+	prepared, err := a.Prepare([]PackageAdjust{
+		{Label: "1A4FF0100000022000000001", Quantity: -500, UnitOfMeasure: "Milligrams", AdjustmentReason: "drying"},
+		{Label: "1A4FF0100000022000000001", Quantity: -9.5, UnitOfMeasure: "g", AdjustmentReason: "Waste", ReasonNote: &note},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Drying", prepared[0].AdjustmentReason)
+	assert.Equal(t, "Grams", prepared[0].UnitOfMeasure)
+	assert.InDelta(t, -0.5, prepared[0].Quantity, 1e-9)
+	assert.Equal(t, "Grams", prepared[1].UnitOfMeasure)
+
+	_, err = a.Adjust([]PackageAdjust{
+		{Label: "1A4FF0100000022000000001", Quantity: -11, UnitOfMeasure: "Grams", AdjustmentReason: "Drying"},
+		{Label: "1A4FF0100000022000000002", Quantity: -1, UnitOfMeasure: "Each", AdjustmentReason: "Waste"},
+		{Label: "1A4FF0100000022000000002", Quantity: -1, UnitOfMeasure: "Grams", AdjustmentReason: "Theft"},
+	})
+	var aerr *PackageAdjustValidationError
+	assert.True(t, errors.As(err, &aerr))
+	assert.Len(t, aerr.Problems, 4)
+	assert.Empty(t, fc.bodies)
+
+	_, err = a.Adjust([]PackageAdjust{
+		{Label: "1A4FF0100000022000000002", Quantity: -1, AdjustmentReason: "Drying"},
+	})
+	assert.NoError(t, err)
+
+	var posted []PackageAdjust
+	assert.NoError(t, json.Unmarshal(fc.bodies["packages/v1/adjust?licenseNumber="+licenseNumber], &posted))
+	assert.Equal(t, "Each", posted[0].UnitOfMeasure)
+}
+
+func TestPackageAdjusterUnknownUnit(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"packages/v1/adjust/reasons":           `[{"Name": "Drying", "RequiresNote": false}]`,
+		"packages/v1/1A4FF0100000022000000003": `{"Label": "1A4FF0100000022000000003", "Quantity": 5, "UnitOfMeasureName": "Packs", "UnitOfMeasureAbbreviation": "pk"}`,
+	})
+	a := MakePackageAdjuster(&Metrc{Client: fc}, licenseNumber)
+
+	// Units the converter does not know are accepted when they are the Package's own unit.
+	prepared, err := a.Prepare([]PackageAdjust{
+		{Label: "1A4FF0100000022000000003", Quantity: -1, AdjustmentReason: "Drying"},
+		{Label: "1A4FF0100000022000000003", Quantity: -1, UnitOfMeasure: "Packs", AdjustmentReason: "Drying"},
+		{Label: "1A4FF0100000022000000003", Quantity: -1, UnitOfMeasure: "pk", AdjustmentReason: "Drying"},
+	})
+	assert.NoError(t, err)
+	for _, adj := range prepared {
+		assert.Equal(t, "Packs", adj.UnitOfMeasure)
+		assert.Equal(t, -1.0, adj.Quantity)
+	}
+
+	_, err = a.Prepare([]PackageAdjust{
+		{Label: "1A4FF0100000022000000003", Quantity: -1, UnitOfMeasure: "Grams", AdjustmentReason: "Drying"},
+	})
+	var aerr *PackageAdjustValidationError
+	assert.True(t, errors.As(err, &aerr))
+	assert.Len(t, aerr.Problems, 1)
+}
@@ -3,6 +3,7 @@ package metrc
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // PackageGet represents a Package in a GET request from Metrc.
@@ -15,6 +16,8 @@ type PackageGet struct {
 	LocationId                          *int     `json:"LocationId"`
 	LocationName                        *string  `json:"LocationName"`
 	LocationTypeName                    *string  `json:"LocationTypeName"`
+	SublocationId                       *int     `json:"SublocationId"`
+	SublocationName                     *string  `json:"SublocationName"`
 	Quantity                            float64  `json:"Quantity"`
 	UnitOfMeasureName                   string   `json:"UnitOfMeasureName"`
 	UnitOfMeasureAbbreviation           string   `json:"UnitOfMeasureAbbreviation"`
@@ -78,6 +81,7 @@ type PackageAdjustReasons struct {
 type PackagePost struct {
 	Tag                        string       `json:"Tag"`
 	Location                   *string      `json:"Location"`
+	Sublocation                *string      `json:"Sublocation,omitempty"`
 	Item                       string       `json:"Item"`
 	Quantity                   float64      `json:"Quantity"`
 	UnitOfMeasure              string       `json:"UnitOfMeasure"`
@@ -90,6 +94,7 @@ type PackagePost struct {
 	UseSameItem                bool         `json:"UseSameItem"`
 	ActualDate                 string       `json:"ActualDate"`
 	Ingredients                []Ingredient `json:"Ingredients"`
+	RequiredLabTestBatches     []string     `json:"RequiredLabTestBatches,omitempty"` // Only used by `PostPackagesCreateTesting`.
 }
 
 // Ingredient represents an Ingredient within a PackagePost.
@@ -100,6 +105,18 @@ type Ingredient struct {
 	UnitOfMeasure string  `json:"UnitOfMeasure"`
 }
 
+// packagePostLabels returns the tags and ingredient labels referenced by packages.
+func packagePostLabels(packages []PackagePost) []string {
+	var labels []string
+	for _, p := range packages {
+		labels = append(labels, p.Tag)
+		for _, i := range p.Ingredients {
+			labels = append(labels, i.Package)
+		}
+	}
+	return labels
+}
+
 // GetPackagesById gets a Package by ID.
 // See: https://api-ca.metrc.com/Documentation/#Packages.get_packages_v1_{id}
 func (m *Metrc) GetPackagesById(id int, licenseNumber *string) (PackageGet, error) {
@@ -125,6 +142,11 @@ func (m *Metrc) GetPackagesById(id int, licenseNumber *string) (PackageGet, erro
 // GetPackagesByLabel gets a Package by its `Label`.
 // See: https://api-ca.metrc.com/Documentation/#Packages.get_packages_v1_{label}
 func (m *Metrc) GetPackagesByLabel(label string, licenseNumber *string) (PackageGet, error) {
+	err := m.checkLabels(label)
+	if err != nil {
+		return PackageGet{}, err
+	}
+
 	endpoint := fmt.Sprintf("packages/v1/%s", label)
 	if licenseNumber != nil {
 		endpoint += fmt.Sprintf("?licenseNumber=%s", *licenseNumber)
@@ -163,28 +185,12 @@ func (m *Metrc) GetPackagesInactive(licenseNumber string, lastModifiedStart *str
 }
 
 func (m *Metrc) getPackages(endpointName string, licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) ([]PackageGet, error) {
-	endpoint := fmt.Sprintf("packages/v1/%s", endpointName)
-	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
-
-	if lastModifiedStart != nil {
-		endpoint += fmt.Sprintf("&lastModifiedStart=%s", *lastModifiedStart)
-	}
-
-	if lastModifiedEnd != nil {
-		endpoint += fmt.Sprintf("&lastModifiedEnd=%s", *lastModifiedEnd)
-	}
-
 	var pr []PackageGet
-	body, err := m.Client.Get(endpoint)
+	err := m.getList("packages", endpointName, licenseNumber, lastModifiedParams(lastModifiedStart, lastModifiedEnd), &pr)
 	if err != nil {
 		return pr, fmt.Errorf("could not get packages from metrc: %s", err)
 	}
 
-	err = json.Unmarshal(body, &pr)
-	if err != nil {
-		return pr, fmt.Errorf("could not unmarshal body: %s", err)
-	}
-
 	return pr, nil
 }
 
@@ -229,6 +235,17 @@ func (m *Metrc) GetPackagesAdjustReasons(licenseNumber string) ([]PackageAdjustR
 // PostPackagesCreate creates new Packages.
 // See: https://api-ca.metrc.com/Documentation/#Packages.post_packages_v1_create
 func (m *Metrc) PostPackagesCreate(packages []PackagePost, licenseNumber string) ([]byte, error) {
+	labels := packagePostLabels(packages)
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "create packages from packages", "CanCreateDerivedPackages")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("packages/v1/create?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(packages)
@@ -245,8 +262,20 @@ func (m *Metrc) PostPackagesCreate(packages []PackagePost, licenseNumber string)
 }
 
 // PostPackagesCreateTesting creates Packages for testing.
+// Set `PackagePost.RequiredLabTestBatches` to names from `GetLabTestsBatches` when the facility `CanRequirePackageSampleLabTestBatches`.
 // See: https://api-ca.metrc.com/Documentation/#Packages.post_packages_v1_create_testing
 func (m *Metrc) PostPackagesCreateTesting(packages []PackagePost, licenseNumber string) ([]byte, error) {
+	labels := packagePostLabels(packages)
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "create testing packages", "CanSubmitPackagesForTesting")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("packages/v1/create/testing?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(packages)
@@ -264,6 +293,17 @@ func (m *Metrc) PostPackagesCreateTesting(packages []PackagePost, licenseNumber
 // PostPackagesCreatePlanting creates Packages from Planting.
 // See: https://api-ca.metrc.com/Documentation/#Packages.post_packages_v1_create_plantings
 func (m *Metrc) PostPackagesCreatePlantings(packages []PackagePost, licenseNumber string) ([]byte, error) {
+	labels := packagePostLabels(packages)
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "create plantings from packages", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("packages/v1/create/plantings?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(packages)
@@ -288,6 +328,15 @@ type PackageItem struct {
 // PostPackagesChangeItem changes the Item on a Package.
 // See: https://api-ca.metrc.com/Documentation/#Packages.post_packages_v1_change_item
 func (m *Metrc) PostPackagesChangeItem(packageItems []PackageItem, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packageItems {
+		labels = append(labels, p.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("packages/v1/change/item?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(packageItems)
@@ -313,6 +362,15 @@ type PackageNote struct {
 // PutPackagesChangeNote changes the Note on a Package.
 // See: https://api-ca.metrc.com/Documentation/#Packages.put_packages_v1_change_note
 func (m *Metrc) PutPackagesChangeNote(packageNotes []PackageNote, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packageNotes {
+		labels = append(labels, p.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("packages/v1/change/note?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(packageNotes)
@@ -331,14 +389,29 @@ func (m *Metrc) PutPackagesChangeNote(packageNotes []PackageNote, licenseNumber
 // PackageLocation represents a Package (via its Label), location, and move date.
 // See: https://api-ca.metrc.com/Documentation/#Packages.post_packages_v1_change_locations
 type PackageLocation struct {
-	Label    string `json:"Label"`
-	Location string `json:"Location"`
-	MoveDate string `json:"MoveDate"`
+	Label       string  `json:"Label"`
+	Location    string  `json:"Location"`
+	Sublocation *string `json:"Sublocation,omitempty"`
+	MoveDate    string  `json:"MoveDate"`
 }
 
 // PostPackagesChangeLocations changes the Location on a Package.
 // See: https://api-ca.metrc.com/Documentation/#Packages.post_packages_v1_change_locations
 func (m *Metrc) PostPackagesChangeLocations(packageLocations []PackageLocation, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packageLocations {
+		labels = append(labels, p.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "change package locations", "CanUpdateLocationsOnPackages")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("packages/v1/change/locations?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(packageLocations)
@@ -368,6 +441,15 @@ type PackageAdjust struct {
 // PostPackagesAdjust changes adjustment metadata on a Package.
 // See: https://api-ca.metrc.com/Documentation/#Packages.post_packages_v1_adjust
 func (m *Metrc) PostPackagesAdjust(packageAdjusts []PackageAdjust, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packageAdjusts {
+		labels = append(labels, p.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("packages/v1/adjust?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(packageAdjusts)
@@ -393,6 +475,15 @@ type PackageFinish struct {
 // PostPackagesFinish finishes a Package.
 // See: https://api-ca.metrc.com/Documentation/#Packages.post_packages_v1_finish
 func (m *Metrc) PostPackagesFinish(packageFinishes []PackageFinish, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packageFinishes {
+		labels = append(labels, p.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("packages/v1/finish?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(packageFinishes)
@@ -417,6 +508,15 @@ type PackageUnfinish struct {
 // PostPackagesUnfinish unfinishes Packages.
 // See: https://api-ca.metrc.com/Documentation/#Packages.post_packages_v1_unfinish
 func (m *Metrc) PostPackagesUnfinish(packageUnfinishes []PackageUnfinish, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packageUnfinishes {
+		labels = append(labels, p.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("packages/v1/unfinish?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(packageUnfinishes)
@@ -444,6 +544,20 @@ type PackageRemediate struct {
 // PostPackagesRemediate remediates Packages.
 // See: https://api-ca.metrc.com/Documentation/#Packages.post_packages_v1_remediate
 func (m *Metrc) PostPackagesRemediate(packageRemediates []PackageRemediate, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packageRemediates {
+		labels = append(labels, p.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "remediate packages", "CanRemediatePackagesWithFailedLabResults")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("packages/v1/remediate?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(packageRemediates)
@@ -458,3 +572,249 @@ func (m *Metrc) PostPackagesRemediate(packageRemediates []PackageRemediate, lice
 
 	return resp, nil
 }
+
+// PackageFlag identifies a Package (via its Label) to flag or unflag as a donation or trade sample.
+// See: https://api-ca.metrc.com/Documentation/#Packages.put_packages_v1_donation_flag
+type PackageFlag struct {
+	Label string `json:"PackageLabel"`
+}
+
+// helper method to flag or unflag Packages, e.g. "donation/flag".
+func (m *Metrc) putPackagesFlag(action string, packageFlags []PackageFlag, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packageFlags {
+		labels = append(labels, p.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := fmt.Sprintf("packages/v1/%s?licenseNumber=%s", action, licenseNumber)
+
+	body, err := json.Marshal(packageFlags)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal package-flags: %s", err)
+	}
+
+	resp, err := m.Client.Put(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed putting package-flags to %s: %s", action, err)
+	}
+
+	return resp, nil
+}
+
+// PutPackagesDonationFlag flags Packages as donations. The facility must be able to `CanDonatePackages`.
+// See: https://api-ca.metrc.com/Documentation/#Packages.put_packages_v1_donation_flag
+func (m *Metrc) PutPackagesDonationFlag(packageFlags []PackageFlag, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "flag donations", "CanDonatePackages")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return m.putPackagesFlag("donation/flag", packageFlags, licenseNumber)
+}
+
+// PutPackagesDonationUnflag removes the donation flag from Packages.
+// See: https://api-ca.metrc.com/Documentation/#Packages.put_packages_v1_donation_unflag
+func (m *Metrc) PutPackagesDonationUnflag(packageFlags []PackageFlag, licenseNumber string) ([]byte, error) {
+	return m.putPackagesFlag("donation/unflag", packageFlags, licenseNumber)
+}
+
+// PutPackagesTradeSampleFlag flags Packages as trade samples. The facility must be able to `CanCreateTradeSamplePackages`.
+// See: https://api-ca.metrc.com/Documentation/#Packages.put_packages_v1_tradesample_flag
+func (m *Metrc) PutPackagesTradeSampleFlag(packageFlags []PackageFlag, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "flag trade samples", "CanCreateTradeSamplePackages")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return m.putPackagesFlag("tradesample/flag", packageFlags, licenseNumber)
+}
+
+// PutPackagesTradeSampleUnflag removes the trade sample flag from Packages.
+// See: https://api-ca.metrc.com/Documentation/#Packages.put_packages_v1_tradesample_unflag
+func (m *Metrc) PutPackagesTradeSampleUnflag(packageFlags []PackageFlag, licenseNumber string) ([]byte, error) {
+	return m.putPackagesFlag("tradesample/unflag", packageFlags, licenseNumber)
+}
+
+// PackageDecontaminate contains the information to decontaminate a package.
+// See: https://api-ca.metrc.com/Documentation/#Packages.put_packages_v1_decontaminate
+type PackageDecontaminate struct {
+	Label      string `json:"PackageLabel"`
+	MethodName string `json:"DecontaminationMethodName"`
+	Date       string `json:"DecontaminationDate"`
+	Steps      string `json:"DecontaminationSteps"`
+}
+
+// PutPackagesDecontaminate decontaminates Packages.
+// See: https://api-ca.metrc.com/Documentation/#Packages.put_packages_v1_decontaminate
+func (m *Metrc) PutPackagesDecontaminate(packageDecontaminates []PackageDecontaminate, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packageDecontaminates {
+		labels = append(labels, p.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := fmt.Sprintf("packages/v1/decontaminate?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(packageDecontaminates)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal package-decontaminates: %s", err)
+	}
+
+	resp, err := m.Client.Put(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed putting package-decontaminates: %s", err)
+	}
+
+	return resp, nil
+}
+
+// PackageRequiredLabTestBatches represents a Package (via its Label) and the lab test batches it must pass.
+// See: https://api-ca.metrc.com/Documentation/#Packages.put_packages_v1_labtests_required
+type PackageRequiredLabTestBatches struct {
+	Label                  string   `json:"PackageLabel"`
+	RequiredLabTestBatches []string `json:"RequiredLabTestBatches"`
+}
+
+// PutPackagesRequiredLabTestBatches changes the lab test batches required of existing Packages.
+// Batch names come from `GetLabTestsBatches`.
+// See: https://api-ca.metrc.com/Documentation/#Packages.put_packages_v1_labtests_required
+func (m *Metrc) PutPackagesRequiredLabTestBatches(packageBatches []PackageRequiredLabTestBatches, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packageBatches {
+		labels = append(labels, p.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "require lab test batches on packages", "CanRequirePackageSampleLabTestBatches")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := fmt.Sprintf("packages/v1/labtests/required?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(packageBatches)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal package-required-lab-test-batches: %s", err)
+	}
+
+	resp, err := m.Client.Put(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed putting package-required-lab-test-batches: %s", err)
+	}
+
+	return resp, nil
+}
+
+// SourceHarvestNameList splits `PackageGet.SourceHarvestNames` into individual harvest names.
+func (p PackageGet) SourceHarvestNameList() []string {
+	return splitNames(p.SourceHarvestNames)
+}
+
+// splitNames splits a comma separated list of names from Metrc, dropping empty entries.
+func splitNames(names *string) []string {
+	if names == nil {
+		return []string{}
+	}
+
+	var out []string
+	for _, n := range strings.Split(*names, ",") {
+		n = strings.TrimSpace(n)
+		if n != "" {
+			out = append(out, n)
+		}
+	}
+	if out == nil {
+		return []string{}
+	}
+	return out
+}
+
+// PackageSourceHarvest represents a harvest that a Package was created from.
+// See: https://api-ca.metrc.com/Documentation/#Packages.get_packages_v1_{id}_source_harvests
+type PackageSourceHarvest struct {
+	HarvestId        int     `json:"HarvestId"`
+	HarvestName      string  `json:"HarvestName"`
+	HarvestStartDate string  `json:"HarvestStartDate"`
+	StrainName       *string `json:"StrainName"`
+	Weight           float64 `json:"Weight"`
+	UnitOfWeightName string  `json:"UnitOfWeightName"`
+}
+
+// PackageSourcePackage represents a Package that another Package was created from.
+// See: https://api-ca.metrc.com/Documentation/#Packages.get_packages_v1_{id}_source_packages
+type PackageSourcePackage struct {
+	PackageId         int     `json:"PackageId"`
+	PackageLabel      string  `json:"PackageLabel"`
+	Quantity          float64 `json:"Quantity"`
+	UnitOfMeasureName string  `json:"UnitOfMeasureName"`
+	ProductName       string  `json:"ProductName"`
+	PackagedDate      string  `json:"PackagedDate"`
+}
+
+// GetPackagesSourceHarvests gets the harvests a Package was created from.
+// See: https://api-ca.metrc.com/Documentation/#Packages.get_packages_v1_{id}_source_harvests
+func (m *Metrc) GetPackagesSourceHarvests(id int, licenseNumber string) ([]PackageSourceHarvest, error) {
+	endpoint := fmt.Sprintf("packages/v1/%d/source/harvests?licenseNumber=%s", id, licenseNumber)
+
+	var sh []PackageSourceHarvest
+	resp, err := m.Client.Get(endpoint)
+	if err != nil {
+		return sh, fmt.Errorf("could not get source harvests for package %d: %s", id, err)
+	}
+
+	err = json.Unmarshal(resp, &sh)
+	if err != nil {
+		return sh, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return sh, nil
+}
+
+// GetPackagesSourcePackages gets the Packages that a Package was created from.
+// See: https://api-ca.metrc.com/Documentation/#Packages.get_packages_v1_{id}_source_packages
+func (m *Metrc) GetPackagesSourcePackages(id int, licenseNumber string) ([]PackageSourcePackage, error) {
+	endpoint := fmt.Sprintf("packages/v1/%d/source/packages?licenseNumber=%s", id, licenseNumber)
+
+	var sp []PackageSourcePackage
+	resp, err := m.Client.Get(endpoint)
+	if err != nil {
+		return sp, fmt.Errorf("could not get source packages for package %d: %s", id, err)
+	}
+
+	err = json.Unmarshal(resp, &sp)
+	if err != nil {
+		return sp, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return sp, nil
+}
+
+// GetPackagesChildren gets the Packages created from a Package.
+// The results use `PackageSourcePackage`, where each entry is a child rather than a source.
+// See: https://api-ca.metrc.com/Documentation/#Packages.get_packages_v1_{id}_children
+func (m *Metrc) GetPackagesChildren(id int, licenseNumber string) ([]PackageSourcePackage, error) {
+	endpoint := fmt.Sprintf("packages/v1/%d/children?licenseNumber=%s", id, licenseNumber)
+
+	var cp []PackageSourcePackage
+	resp, err := m.Client.Get(endpoint)
+	if err != nil {
+		return cp, fmt.Errorf("could not get child packages for package %d: %s", id, err)
+	}
+
+	err = json.Unmarshal(resp, &cp)
+	if err != nil {
+		return cp, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return cp, nil
+}
@@ -100,3 +100,30 @@ func TestPackagesCreate_Integration(t *testing.T) {
 
 	// TODO: Change the packages note.
 }
+
+func TestPackagesDonationFlag_Integration(t *testing.T) {
+	// TODO: Implement once Package works.
+}
+
+func TestPackagesTradeSampleFlag_Integration(t *testing.T) {
+	// TODO: Implement once Package works.
+}
+
+func TestPackagesDecontaminate_Integration(t *testing.T) {
+	// TODO: Implement once Package works.
+}
+
+func TestPackagesRequiredLabTestBatches_Integration(t *testing.T) {
+	// TODO: Implement once Package works.
+}
+
+func TestPackagesSourceHarvestNameList(t *testing.T) {
+	names := "Harvest A, Harvest B,,Harvest C"
+	p := PackageGet{SourceHarvestNames: &names}
+	assert.Equal(t, []string{"Harvest A", "Harvest B", "Harvest C"}, p.SourceHarvestNameList())
+	assert.Equal(t, []string{}, PackageGet{}.SourceHarvestNameList())
+}
+
+func TestPackagesLineage_Integration(t *testing.T) {
+	// TODO: Implement once Package works.
+}
@@ -14,6 +14,8 @@ type PlantBatch struct {
 	LocationId           *int    `json:"LocationId"`
 	LocationName         *string `json:"LocationName"`
 	LocationTypeName     *string `json:"LocationTypeName"`
+	SublocationId        *int    `json:"SublocationId"`
+	SublocationName      *string `json:"SublocationName"`
 	StrainId             *int    `json:"StrainId"`
 	StrainName           *string `json:"StrainName"`
 	PatientLicenseNumber *int    `json:"PatientLicenseNumber"`
@@ -52,26 +54,12 @@ func (m *Metrc) GetPlantBatchesById(id int, licenseNumber *string) (PlantBatch,
 }
 
 func (m *Metrc) getPlantBatchesByStatus(status string, licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) ([]PlantBatch, error) {
-	endpoint := fmt.Sprintf("plantbatches/v1/%s", status)
-	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
-	if lastModifiedStart != nil {
-		endpoint += fmt.Sprintf("?lastModifiedStart=%s", *lastModifiedStart)
-	}
-	if lastModifiedEnd != nil {
-		endpoint += fmt.Sprintf("?lastModifiedEnd=%s", *lastModifiedEnd)
-	}
-
 	var pbr []PlantBatch
-	responseBody, err := m.Client.Get(endpoint)
+	err := m.getList("plantbatches", status, licenseNumber, lastModifiedParams(lastModifiedStart, lastModifiedEnd), &pbr)
 	if err != nil {
 		return pbr, fmt.Errorf("could not get plant batches by status response: %s", err)
 	}
 
-	err = json.Unmarshal(responseBody, &pbr)
-	if err != nil {
-		return pbr, fmt.Errorf("could not unmarshal response: %s", err)
-	}
-
 	return pbr, nil
 }
 
@@ -124,6 +112,7 @@ type PlantBatchPlanting struct {
 	Count                int     `json:"Count"`
 	Strain               string  `json:"Strain"`
 	Location             *string `json:"Location"`
+	Sublocation          *string `json:"Sublocation,omitempty"`
 	PatientLicenseNumber string  `json:"PatientLicenseNumber"`
 	ActualDate           string  `json:"ActualDate"`
 }
@@ -131,6 +120,11 @@ type PlantBatchPlanting struct {
 // PostPlantBatchesCreatePlantings creates a planting of a new plant batch.
 // See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_createplantings.POST
 func (m *Metrc) PostPlantBatchesCreatePlantings(batches []PlantBatchPlanting, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "create plant batch plantings", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plantbatches/v1/createplantings"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -154,6 +148,7 @@ type PlantBatchPackage struct {
 	PlantBatch           string  `json:"PlantBatch"`
 	Count                int     `json:"Count"`
 	Location             *string `json:"Location"`
+	Sublocation          *string `json:"Sublocation,omitempty"`
 	Item                 string  `json:"Item"`
 	Tag                  string  `json:"Tag"`
 	PatientLicenseNumber string  `json:"PatientLicenseNumber"`
@@ -166,6 +161,20 @@ type PlantBatchPackage struct {
 // PostPlantBatchesCreatePackages creates a package of a Plant Batch in Metrc.
 // See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_createpackages.POST
 func (m *Metrc) PostPlantBatchesCreatePackages(packages []PlantBatchPackage, licenseNumber string, isFromMotherPlant *bool) ([]byte, error) {
+	var labels []string
+	for _, p := range packages {
+		labels = append(labels, p.Tag)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "create plant batch packages", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plantbatches/v1/createpackages"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 	if isFromMotherPlant != nil {
@@ -200,6 +209,11 @@ type PlantBatchSplit struct {
 // PostPlantBatchesSplit posts split plant batches.
 // See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_split.POST
 func (m *Metrc) PostPlantBatchesSplit(batches []PlantBatchSplit, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "split plant batches", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plantbatches/v1/split"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -219,6 +233,20 @@ func (m *Metrc) PostPlantBatchesSplit(batches []PlantBatchSplit, licenseNumber s
 // PostPlantBatchesCreatePackagesFromMotherPlant creates packages of plant batches from a mother plant.
 // See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_create_packages_frommotherplant.POST
 func (m *Metrc) PostPlantBatchesCreatePackagesFromMotherPlant(packages []PlantBatchPackage, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packages {
+		labels = append(labels, p.Tag)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "create packages from mother plants", "CanGrowPlants", "PlantBatchesCanContainMotherPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plantbatches/v1/create/packages/frommotherplant"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -250,6 +278,22 @@ type PlantBatchGrowthPhase struct {
 // PostPlantBatchesChangeGrowthPhase changes the growth phase of batches.
 // See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_changegrowthphase.POST
 func (m *Metrc) PostPlantBatchesChangeGrowthPhase(batches []PlantBatchGrowthPhase, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, b := range batches {
+		if b.StartingTag != "" {
+			labels = append(labels, b.StartingTag)
+		}
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "change plant batch growth phases", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plantbatches/v1/changegrowthphase"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -269,14 +313,20 @@ func (m *Metrc) PostPlantBatchesChangeGrowthPhase(batches []PlantBatchGrowthPhas
 // PlantBatchMove represents data to move a plant batch.
 // See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.put_plantbatches_v1_moveplantbatches.PUT
 type PlantBatchMove struct {
-	Name     string `json:"Name"`
-	Location string `json:"Location"`
-	MoveDate string `json:"MoveDate"`
+	Name        string  `json:"Name"`
+	Location    string  `json:"Location"`
+	Sublocation *string `json:"Sublocation,omitempty"`
+	MoveDate    string  `json:"MoveDate"`
 }
 
 // PutPlantBatchesMove moves a Plant Batch in Metrc.
 // See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.put_plantbatches_v1_moveplantbatches.PUT
 func (m *Metrc) PutPlantBatchesMove(batches []PlantBatchMove, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "move plant batches", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "/plantbatches/v1/moveplantbatches"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -318,6 +368,11 @@ type PlantBatchAdditiveActiveIngredient struct {
 // PostPlantBatchesAdditives posts plant batch additives.
 // See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_additives.POST
 func (m *Metrc) PostPlantBatchesAdditives(additives []PlantBatchAdditive, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "record plant batch additives", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plantbatches/v1/additives"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -346,6 +401,11 @@ type PlantBatchDestroy struct {
 // PostPlantBatchesDestroy destroys plant batches.
 // See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_destroy.POST
 func (m *Metrc) PostPlantBatchesDestroy(batches []PlantBatchDestroy, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "destroy plant batches", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plantbatches/v1/destroy"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -361,3 +421,106 @@ func (m *Metrc) PostPlantBatchesDestroy(batches []PlantBatchDestroy, licenseNumb
 
 	return resp, nil
 }
+
+// PlantBatchAdjust represents an adjustment to the count of a plant batch.
+// See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_adjust.POST
+type PlantBatchAdjust struct {
+	PlantBatch     string `json:"PlantBatch"`
+	Count          int    `json:"Count"`
+	AdjustReason   string `json:"AdjustReason"`
+	ReasonNote     string `json:"ReasonNote"`
+	AdjustmentDate string `json:"AdjustmentDate"`
+}
+
+// PostPlantBatchesAdjust adjusts the counts of plant batches.
+// See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_adjust.POST
+func (m *Metrc) PostPlantBatchesAdjust(batches []PlantBatchAdjust, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "adjust plant batches", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := "plantbatches/v1/adjust"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(batches)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal body: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not post body: %s", err)
+	}
+
+	return resp, nil
+}
+
+// PlantBatchChangeStrain represents a plant batch whose strain is changed.
+// See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_changestrain.POST
+type PlantBatchChangeStrain struct {
+	PlantBatch string `json:"PlantBatch"`
+	Strain     string `json:"Strain"`
+	ActualDate string `json:"ActualDate"`
+}
+
+// PostPlantBatchesChangeStrain changes the strain of plant batches.
+// See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_changestrain.POST
+func (m *Metrc) PostPlantBatchesChangeStrain(batches []PlantBatchChangeStrain, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "change plant batch strains", "CanGrowPlants", "CanUpdatePlantStrains")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := "plantbatches/v1/changestrain"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(batches)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal body: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not post body: %s", err)
+	}
+
+	return resp, nil
+}
+
+// PlantBatchWaste represents waste recorded against a plant batch.
+// See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_waste.POST
+type PlantBatchWaste struct {
+	PlantBatch             string  `json:"PlantBatch"`
+	WasteMethodName        string  `json:"WasteMethodName"`
+	WasteMaterialMixed     string  `json:"WasteMaterialMixed"`
+	WasteWeight            float64 `json:"WasteWeight"`
+	WasteUnitOfMeasureName string  `json:"WasteUnitOfMeasureName"`
+	WasteReasonName        string  `json:"WasteReasonName"`
+	ReasonNote             string  `json:"ReasonNote"`
+	ActualDate             string  `json:"ActualDate"`
+}
+
+// PostPlantBatchesWaste records waste for plant batches.
+// See: https://testing-api-ca.metrc.com/Documentation#PlantBatches.post_plantbatches_v1_waste.POST
+func (m *Metrc) PostPlantBatchesWaste(wastes []PlantBatchWaste, licenseNumber string) ([]byte, error) {
+	err := m.checkCapabilities(licenseNumber, "record plant batch waste", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := "plantbatches/v1/waste"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(wastes)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal body: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not post body: %s", err)
+	}
+
+	return resp, nil
+}
@@ -61,3 +61,15 @@ func TestPlantBatchesAdditives_Integration(t *testing.T) {
 func TestPlantBatchesDestroy_Integration(t *testing.T) {
 	// TODO: Implement.
 }
+
+func TestPlantBatchesAdjust_Integration(t *testing.T) {
+	// TODO: Implement.
+}
+
+func TestPlantBatchesChangeStrain_Integration(t *testing.T) {
+	// TODO: Implement.
+}
+
+func TestPlantBatchesWaste_Integration(t *testing.T) {
+	// TODO: Implement.
+}
@@ -0,0 +1,328 @@
+package metrc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// PlantState is where a Plant is in its lifecycle: vegetative, then flowering, then harvested, or destroyed at any point before harvest.
+type PlantState string
+
+// The lifecycle states of a Plant.
+const (
+	PlantStateVegetative PlantState = "Vegetative"
+	PlantStateFlowering  PlantState = "Flowering"
+	PlantStateHarvested  PlantState = "Harvested"
+	PlantStateDestroyed  PlantState = "Destroyed"
+)
+
+// PlantTransition is a write that moves a Plant through its lifecycle.
+type PlantTransition string
+
+// The lifecycle transitions of a Plant, and the write that makes each one.
+const (
+	PlantTransitionVegetative PlantTransition = "change growth phase to vegetative" // `PostPlantsChangeGrowthPhases`
+	PlantTransitionFlowering  PlantTransition = "change growth phase to flowering"  // `PostPlantsChangeGrowthPhases`
+	PlantTransitionManicure   PlantTransition = "manicure"                          // `PostPlantsManicure`
+	PlantTransitionHarvest    PlantTransition = "harvest"                           // `PostPlantsHarvest`
+	PlantTransitionDestroy    PlantTransition = "destroy"                           // `PostPlantsDestroy`
+)
+
+// plantTransitions lists the states each transition can be made from.
+var plantTransitions = map[PlantTransition][]PlantState{
+	PlantTransitionVegetative: {PlantStateVegetative},
+	PlantTransitionFlowering:  {PlantStateVegetative},
+	PlantTransitionManicure:   {PlantStateFlowering},
+	PlantTransitionHarvest:    {PlantStateFlowering},
+	PlantTransitionDestroy:    {PlantStateVegetative, PlantStateFlowering},
+}
+
+// PlantStateOf derives the lifecycle state of a Plant from its state, growth phase, and dates.
+func PlantStateOf(p Plant) PlantState {
+	switch {
+	case p.DestroyedDate != nil || strings.EqualFold(p.State, "Destroyed"):
+		return PlantStateDestroyed
+	case strings.EqualFold(p.State, "Harvested"):
+		return PlantStateHarvested
+	case strings.EqualFold(p.GrowthPhase, "Flowering") || p.FloweringDate != nil:
+		return PlantStateFlowering
+	}
+	return PlantStateVegetative
+}
+
+// CheckPlantTransition explains why a transition is illegal for a Plant, or returns nil if it is legal.
+func CheckPlantTransition(p Plant, t PlantTransition) error {
+	from, ok := plantTransitions[t]
+	if !ok {
+		return fmt.Errorf("unknown plant transition %q", t)
+	}
+
+	state := PlantStateOf(p)
+	if p.IsOnHold {
+		return fmt.Errorf("cannot %s plant %s: it is on hold", t, p.Label)
+	}
+	for _, s := range from {
+		if s == state {
+			return nil
+		}
+	}
+
+	var allowed []string
+	for _, s := range from {
+		allowed = append(allowed, strings.ToLower(string(s)))
+	}
+	return fmt.Errorf("cannot %s plant %s: it is %s, but must be %s", t, p.Label, strings.ToLower(string(state)), strings.Join(allowed, " or "))
+}
+
+// growthPhaseTransition maps the growth phase of a `PlantChangeGrowthPhase` to its transition.
+func growthPhaseTransition(phase string) (PlantTransition, error) {
+	switch {
+	case strings.EqualFold(phase, "Vegetative"):
+		return PlantTransitionVegetative, nil
+	case strings.EqualFold(phase, "Flowering"):
+		return PlantTransitionFlowering, nil
+	}
+	return "", fmt.Errorf("unknown growth phase %q", phase)
+}
+
+// PlantTransitionProblem is an illegal transition in a batch of writes.
+type PlantTransitionProblem struct {
+	Index   int
+	Plant   string
+	Message string
+}
+
+func (p PlantTransitionProblem) String() string {
+	return fmt.Sprintf("entry %d (%s): %s", p.Index, p.Plant, p.Message)
+}
+
+// PlantTransitionError lists every illegal transition found in a batch of writes.
+type PlantTransitionError struct {
+	Problems []PlantTransitionProblem
+}
+
+func (e *PlantTransitionError) Error() string {
+	var ps []string
+	for _, p := range e.Problems {
+		ps = append(ps, p.String())
+	}
+	return fmt.Sprintf("illegal plant transitions: %s", strings.Join(ps, "; "))
+}
+
+// PlantLifecycle refuses illegal lifecycle writes for the Plants of a license before they are sent to Metrc.
+// Plants are looked up with `GetPlantsByLabel` or `GetPlantsById` unless already known from `Observe`,
+// and their cached state is updated after each successful write.
+type PlantLifecycle struct {
+	Metrc         *Metrc
+	LicenseNumber string
+
+	mu     sync.Mutex
+	plants map[string]Plant
+	labels map[int]string
+}
+
+// MakePlantLifecycle creates a lifecycle for the Plants of a license.
+func MakePlantLifecycle(m *Metrc, licenseNumber string) *PlantLifecycle {
+	return &PlantLifecycle{
+		Metrc:         m,
+		LicenseNumber: licenseNumber,
+		plants:        map[string]Plant{},
+		labels:        map[int]string{},
+	}
+}
+
+// Observe caches Plants, e.g. from `GetPlantsVegetative`, so they are not looked up again.
+func (l *PlantLifecycle) Observe(plants ...Plant) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, p := range plants {
+		l.observeLocked(p)
+	}
+}
+
+// observeLocked must be called with l.mu held.
+func (l *PlantLifecycle) observeLocked(p Plant) {
+	l.plants[p.Label] = p
+	if p.Id != 0 {
+		l.labels[p.Id] = p.Label
+	}
+}
+
+// State returns the lifecycle state of a Plant.
+func (l *PlantLifecycle) State(label string) (PlantState, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	p, err := l.plantLocked(nil, &label)
+	if err != nil {
+		return "", err
+	}
+	return PlantStateOf(p), nil
+}
+
+// plantLocked finds a Plant by Id or label, looking it up in Metrc if it is not cached. Must be called with l.mu held.
+func (l *PlantLifecycle) plantLocked(id *int, label *string) (Plant, error) {
+	if label != nil {
+		if p, ok := l.plants[*label]; ok {
+			return p, nil
+		}
+	} else if id != nil {
+		if lb, ok := l.labels[*id]; ok {
+			return l.plants[lb], nil
+		}
+	}
+
+	var p Plant
+	var err error
+	switch {
+	case label != nil:
+		p, err = l.Metrc.GetPlantsByLabel(*label, &l.LicenseNumber)
+	case id != nil:
+		p, err = l.Metrc.GetPlantsById(*id, &l.LicenseNumber)
+	default:
+		return Plant{}, fmt.Errorf("plant needs an Id or Label")
+	}
+	if err != nil {
+		return Plant{}, fmt.Errorf("could not get plant: %s", err)
+	}
+
+	l.observeLocked(p)
+	return p, nil
+}
+
+// check checks a transition for every Plant in a batch. Must be called with l.mu held.
+func (l *PlantLifecycle) check(n int, plant func(i int) (*int, *string), transition func(i int) (PlantTransition, error)) ([]Plant, error) {
+	plants := make([]Plant, n)
+	var problems []PlantTransitionProblem
+	for i := 0; i < n; i++ {
+		id, label := plant(i)
+		name := ""
+		if label != nil {
+			name = *label
+		} else if id != nil {
+			name = fmt.Sprint(*id)
+		}
+
+		p, err := l.plantLocked(id, label)
+		if err == nil {
+			plants[i] = p
+			var t PlantTransition
+			t, err = transition(i)
+			if err == nil {
+				err = CheckPlantTransition(p, t)
+			}
+		}
+		if err != nil {
+			problems = append(problems, PlantTransitionProblem{Index: i, Plant: name, Message: err.Error()})
+		}
+	}
+
+	if len(problems) > 0 {
+		return plants, &PlantTransitionError{Problems: problems}
+	}
+	return plants, nil
+}
+
+// ChangeGrowthPhases checks and posts growth phase changes with `PostPlantsChangeGrowthPhases`.
+func (l *PlantLifecycle) ChangeGrowthPhases(changes []PlantChangeGrowthPhase) ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	plants, err := l.check(len(changes),
+		func(i int) (*int, *string) { return changes[i].Id, changes[i].Label },
+		func(i int) (PlantTransition, error) { return growthPhaseTransition(changes[i].GrowthPhase) })
+	if err != nil {
+		return []byte{}, err
+	}
+
+	resp, err := l.Metrc.PostPlantsChangeGrowthPhases(changes, l.LicenseNumber)
+	if err != nil {
+		return resp, err
+	}
+
+	for i, c := range changes {
+		p := plants[i]
+		if c.NewTag != "" && c.NewTag != p.Label {
+			delete(l.plants, p.Label)
+			p.Label = c.NewTag
+		}
+		p.GrowthPhase = c.GrowthPhase
+		if strings.EqualFold(c.GrowthPhase, "Flowering") {
+			date := c.GrowthDate
+			p.FloweringDate = &date
+		}
+		l.observeLocked(p)
+	}
+	return resp, nil
+}
+
+// Manicure checks and posts manicures with `PostPlantsManicure`.
+func (l *PlantLifecycle) Manicure(plants []PlantManicure) ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err := l.check(len(plants),
+		func(i int) (*int, *string) { return nil, &plants[i].Plant },
+		func(i int) (PlantTransition, error) { return PlantTransitionManicure, nil })
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return l.Metrc.PostPlantsManicure(plants, l.LicenseNumber)
+}
+
+// Harvest checks and posts harvests with `PostPlantsHarvest`.
+func (l *PlantLifecycle) Harvest(plants []PlantHarvest) ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current, err := l.check(len(plants),
+		func(i int) (*int, *string) { return nil, &plants[i].Plant },
+		func(i int) (PlantTransition, error) { return PlantTransitionHarvest, nil })
+	if err != nil {
+		return []byte{}, err
+	}
+
+	resp, err := l.Metrc.PostPlantsHarvest(plants, l.LicenseNumber)
+	if err != nil {
+		return resp, err
+	}
+
+	for i, h := range plants {
+		p := current[i]
+		p.State = string(PlantStateHarvested)
+		date := h.ActualDate
+		p.HarvestedDate = &date
+		l.observeLocked(p)
+	}
+	return resp, nil
+}
+
+// Destroy checks and posts destructions with `PostPlantsDestroy`.
+func (l *PlantLifecycle) Destroy(plants []PlantDestroy) ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current, err := l.check(len(plants),
+		func(i int) (*int, *string) { return plants[i].Id, plants[i].Label },
+		func(i int) (PlantTransition, error) { return PlantTransitionDestroy, nil })
+	if err != nil {
+		return []byte{}, err
+	}
+
+	resp, err := l.Metrc.PostPlantsDestroy(plants, l.LicenseNumber)
+	if err != nil {
+		return resp, err
+	}
+
+	for i, d := range plants {
+		p := current[i]
+		p.State = string(PlantStateDestroyed)
+		date := d.ActualDate
+		p.DestroyedDate = &date
+		l.observeLocked(p)
+	}
+	return resp, nil
+}
@@ -0,0 +1,61 @@
+package metrc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlantLifecycleStates(t *testing.T) {
+	date := "2021-06-01"
+	assert.Equal(t, PlantStateVegetative, PlantStateOf(Plant{State: "Tracked", GrowthPhase: "Vegetative"}))
+	assert.Equal(t, PlantStateFlowering, PlantStateOf(Plant{State: "Tracked", GrowthPhase: "Flowering"}))
+	assert.Equal(t, PlantStateHarvested, PlantStateOf(Plant{State: "Harvested", GrowthPhase: "Flowering"}))
+	assert.Equal(t, PlantStateDestroyed, PlantStateOf(Plant{State: "Tracked", DestroyedDate: &date}))
+
+	assert.NoError(t, CheckPlantTransition(Plant{GrowthPhase: "Vegetative"}, PlantTransitionFlowering))
+	assert.Error(t, CheckPlantTransition(Plant{GrowthPhase: "Vegetative"}, PlantTransitionHarvest))
+	assert.Error(t, CheckPlantTransition(Plant{GrowthPhase: "Flowering"}, PlantTransitionVegetative))
+	assert.Error(t, CheckPlantTransition(Plant{State: "Destroyed"}, PlantTransitionDestroy))
+	assert.Error(t, CheckPlantTransition(Plant{GrowthPhase: "Flowering", IsOnHold: true}, PlantTransitionHarvest))
+}
+
+func TestPlantLifecycle(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"plants/v1/1A4FF0100000022000000003": `{"Id": 3, "Label": "1A4FF0100000022000000003", "State": "Tracked", "GrowthPhase": "Flowering"}`,
+	})
+	l := MakePlantLifecycle(&Metrc{Client: fc}, licenseNumber)
+	l.Observe(
+		Plant{Id: 1, Label: "1A4FF0100000022000000001", State: "Tracked", GrowthPhase: "Vegetative"},
+		Plant{Id: 2, Label: "1A4FF0100000022000000002", State: "Tracked", GrowthPhase: "Vegetative"},
+	)
+
+	_, err := l.Harvest([]PlantHarvest{{Plant: "1A4FF0100000022000000001"}, {Plant: "1A4FF0100000022000000003"}})
+	var terr *PlantTransitionError
+	assert.True(t, errors.As(err, &terr))
+	assert.Len(t, terr.Problems, 1)
+	assert.Equal(t, 0, terr.Problems[0].Index)
+	assert.Empty(t, fc.bodies)
+
+	label := "1A4FF0100000022000000001"
+	_, err = l.ChangeGrowthPhases([]PlantChangeGrowthPhase{{Label: &label, NewTag: "1A4FF0100000022000000009", GrowthPhase: "Flowering", GrowthDate: "2021-06-01"}})
+	assert.NoError(t, err)
+	state, err := l.State("1A4FF0100000022000000009")
+	assert.NoError(t, err)
+	assert.Equal(t, PlantStateFlowering, state)
+
+	id := 2
+	_, err = l.Destroy([]PlantDestroy{{Id: &id, ActualDate: "2021-06-01"}})
+	assert.NoError(t, err)
+	_, err = l.Destroy([]PlantDestroy{{Id: &id, ActualDate: "2021-06-02"}})
+	assert.True(t, errors.As(err, &terr))
+	assert.Contains(t, terr.Problems[0].Message, "destroyed")
+
+	_, err = l.Harvest([]PlantHarvest{{Plant: "1A4FF0100000022000000003", ActualDate: "2021-06-01"}})
+	assert.NoError(t, err)
+	state, err = l.State("1A4FF0100000022000000003")
+	assert.NoError(t, err)
+	assert.Equal(t, PlantStateHarvested, state)
+	assert.Len(t, fc.gets, 1)
+}
@@ -19,6 +19,8 @@ type Plant struct {
 	StrainName                 string  `json:"StrainName"`
 	LocationId                 int     `json:"LocationId"`
 	LocationName               string  `json:"LocationName"`
+	SublocationId              *int    `json:"SublocationId"`
+	SublocationName            *string `json:"SublocationName"`
 	PatientLicenseNumber       int     `json:"PatientLicenseNumber"`
 	HarvestId                  *int    `json:"HarvestId"`
 	HarvestedUnionOfWeightName *string `json:"HarvestedUnionOfWeightName"`
@@ -59,6 +61,11 @@ func (m *Metrc) GetPlantsById(id int, licenseNumber *string) (Plant, error) {
 // GetPlantsByLabel gets Strains with a label.
 // See: https://testing-api-ca.metrc.com/Documentation#Plants.get_plants_v1_{label}.GET
 func (m *Metrc) GetPlantsByLabel(label string, licenseNumber *string) (Plant, error) {
+	err := m.checkLabels(label)
+	if err != nil {
+		return Plant{}, err
+	}
+
 	endpoint := fmt.Sprintf("plants/v1/%s", label)
 	if licenseNumber != nil {
 		endpoint += fmt.Sprintf("?licenseNumber=%s", *licenseNumber)
@@ -80,26 +87,12 @@ func (m *Metrc) GetPlantsByLabel(label string, licenseNumber *string) (Plant, er
 
 // helper function to get Plants of various status.
 func (m *Metrc) getPlantsByStatus(status string, licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) ([]Plant, error) {
-	endpoint := fmt.Sprintf("plants/v1/%s", status)
-	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
-	if lastModifiedStart != nil {
-		endpoint += fmt.Sprintf("?lastModifiedStart=%s", *lastModifiedStart)
-	}
-	if lastModifiedEnd != nil {
-		endpoint += fmt.Sprintf("?lastModifiedEnd=%s", *lastModifiedEnd)
-	}
-
 	var pr []Plant
-	responseBody, err := m.Client.Get(endpoint)
+	err := m.getList("plants", status, licenseNumber, lastModifiedParams(lastModifiedStart, lastModifiedEnd), &pr)
 	if err != nil {
 		return pr, fmt.Errorf("could not get plants: %s", err)
 	}
 
-	err = json.Unmarshal(responseBody, &pr)
-	if err != nil {
-		return pr, fmt.Errorf("could not unmarshal response: %s", err)
-	}
-
 	return pr, nil
 }
 
@@ -264,15 +257,30 @@ func (m *Metrc) GetPlantsWasteReasons(licenseNumber string) ([]PlantWasteReason,
 // PlantMovePost is used in the request body to Move a Plant.
 // See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_moveplants.POST
 type PlantMovePost struct {
-	Id         *int    `json:"Id"`
-	Label      *string `json:"Label"`
-	Location   string  `json:"Location"`
-	ActualDate string  `json:"ActualDate"`
+	Id          *int    `json:"Id"`
+	Label       *string `json:"Label"`
+	Location    string  `json:"Location"`
+	Sublocation *string `json:"Sublocation,omitempty"`
+	ActualDate  string  `json:"ActualDate"`
 }
 
 // PostPlantsMovePlants changes Plants locations within Metrc.
 // See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_moveplants.POST
 func (m *Metrc) PostPlantsMovePlants(movePlants []PlantMovePost, licenseNumber string) ([]byte, error) {
+	var optLabels []*string
+	for _, p := range movePlants {
+		optLabels = append(optLabels, p.Label)
+	}
+	err := m.checkOptionalLabels(optLabels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "move plants", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plants/v1/moveplants"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -291,17 +299,40 @@ func (m *Metrc) PostPlantsMovePlants(movePlants []PlantMovePost, licenseNumber s
 
 // PlantChangeGrowthPhase is used in the request body to change a Plant's growth phase.
 type PlantChangeGrowthPhase struct {
-	Id          *int    `json:"Id"`
-	Label       *string `json:"Label"`
-	NewTag      string  `json:"NewTag"`
-	GrowthPhase string  `json:"GrowthPhase"`
-	NewLocation string  `json:"NewLocation"`
-	GrowthDate  string  `json:"GrowthDate"`
+	Id             *int    `json:"Id"`
+	Label          *string `json:"Label"`
+	NewTag         string  `json:"NewTag"`
+	GrowthPhase    string  `json:"GrowthPhase"`
+	NewLocation    string  `json:"NewLocation"`
+	NewSublocation *string `json:"NewSublocation,omitempty"`
+	GrowthDate     string  `json:"GrowthDate"`
 }
 
 // PostPlantsChangeGrowthPhases changes Plants growth phases within Metrc.
 // See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_changegrowthphases.POST
 func (m *Metrc) PostPlantsChangeGrowthPhases(changes []PlantChangeGrowthPhase, licenseNumber string) ([]byte, error) {
+	var labels []string
+	var optLabels []*string
+	for _, c := range changes {
+		if c.NewTag != "" {
+			labels = append(labels, c.NewTag)
+		}
+		optLabels = append(optLabels, c.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+	err = m.checkOptionalLabels(optLabels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "change plant growth phases", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plants/v1/changegrowthphases"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -334,6 +365,20 @@ type PlantDestroy struct {
 // PostPlantsDestroy is used in the request body to destroy a Plant.
 // See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_destroyplants.POST
 func (m *Metrc) PostPlantsDestroy(plants []PlantDestroy, licenseNumber string) ([]byte, error) {
+	var optLabels []*string
+	for _, p := range plants {
+		optLabels = append(optLabels, p.Label)
+	}
+	err := m.checkOptionalLabels(optLabels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "destroy plants", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plants/v1/destroyplants"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -374,6 +419,20 @@ type PlantAdditiveIngredient struct {
 // PostPlantsAdditives is used in the request body to change the additives for the Plants.
 // See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_additives.POST
 func (m *Metrc) PostPlantsAdditives(additives []PlantAdditivePost, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, a := range additives {
+		labels = append(labels, a.PlantLabels...)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "record plant additives", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plants/v1/additives"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -398,6 +457,7 @@ type PlantCreatePlanting struct {
 	PlantBatchType       string  `json:"PlantBatchType"`
 	PlantCount           int     `json:"PlantCount"`
 	LocationName         *string `json:"LocationName"`
+	SublocationName      *string `json:"SublocationName,omitempty"`
 	StrainName           string  `json:"StrainName"`
 	PatientLicenseNumber string  `json:"PatientLicenseNumber"`
 	ActualDate           string  `json:"ActualDate"`
@@ -406,6 +466,20 @@ type PlantCreatePlanting struct {
 // PostPlantsCreatePlantings creates new plants.
 // See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_create_plantings.POST
 func (m *Metrc) PostPlantsCreatePlantings(plants []PlantCreatePlanting, licenseNumber string) ([]byte, error) {
+	var optLabels []*string
+	for _, p := range plants {
+		optLabels = append(optLabels, p.PlantLabel)
+	}
+	err := m.checkOptionalLabels(optLabels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "create plantings from plants", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plants/v1/create/plantings"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -441,6 +515,20 @@ type PlantCreatePlantBatchPackage struct {
 // PostPlantsCreateBatchPackages creates Plant batch packages.
 // See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_create_plantbatch_packages.POST
 func (m *Metrc) PostPlantsCreateBatchPackages(batches []PlantCreatePlantBatchPackage, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, b := range batches {
+		labels = append(labels, b.PlantLabel, b.PackageTag)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "create plant batch packages from plants", "CanGrowPlants", "CanCreateImmaturePlantPackagesFromPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plants/v1/create/plantbatch/packages"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -464,6 +552,7 @@ type PlantManicure struct {
 	Weight               float64 `json:"Weight"`
 	UnitOfWeight         string  `json:"UnitOfWeight"`
 	DryingLocation       string  `json:"DryingLocation"`
+	DryingSublocation    *string `json:"DryingSublocation,omitempty"`
 	HarvestName          *string `json:"HarvestName"`
 	PatientLicenseNumber string  `json:"PatientLicenseNumber"`
 	ActualDate           string  `json:"ActualDate"`
@@ -472,6 +561,20 @@ type PlantManicure struct {
 // PostPlantsManicure posts the plant to manicure.
 // See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_manicureplants.POST
 func (m *Metrc) PostPlantsManicure(plants []PlantManicure, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range plants {
+		labels = append(labels, p.Plant)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "manicure plants", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plants/v1/manicureplants"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -495,6 +598,7 @@ type PlantHarvest struct {
 	Weight               float64 `json:"Weight"`
 	UnitOfWeight         string  `json:"UnitOfWeight"`
 	DryingLocation       string  `json:"DryingLocation"`
+	DryingSublocation    *string `json:"DryingSublocation,omitempty"`
 	HarvestName          string  `json:"HarvestName"`
 	PatientLicenseNumber string  `json:"PatientLicenseNumber"`
 	ActualDate           string  `json:"ActualDate"`
@@ -503,6 +607,20 @@ type PlantHarvest struct {
 // PostPlantsHarvest posts the plants to harvest.
 // See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_harvestplants.POST
 func (m *Metrc) PostPlantsHarvest(plants []PlantHarvest, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range plants {
+		labels = append(labels, p.Plant)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "harvest plants", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := "plants/v1/harvestplants"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 
@@ -518,3 +636,140 @@ func (m *Metrc) PostPlantsHarvest(plants []PlantHarvest, licenseNumber string) (
 
 	return resp, nil
 }
+
+// PlantReplaceTag is used in the request body to replace the tag on a Plant.
+// See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_replacetags.POST
+type PlantReplaceTag struct {
+	Id          *int    `json:"Id"`
+	Label       *string `json:"Label"`
+	NewTag      string  `json:"NewTag"`
+	ReplaceDate string  `json:"ReplaceDate"`
+}
+
+// PostPlantsReplaceTags replaces the tags on Plants, e.g. when a tag is damaged.
+// See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_replacetags.POST
+func (m *Metrc) PostPlantsReplaceTags(tags []PlantReplaceTag, licenseNumber string) ([]byte, error) {
+	var labels []string
+	var optLabels []*string
+	for _, t := range tags {
+		labels = append(labels, t.NewTag)
+		optLabels = append(optLabels, t.Label)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+	err = m.checkOptionalLabels(optLabels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "replace plant tags", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := "plants/v1/replacetags"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(tags)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal replace tags: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed posting replace tags: %s", err)
+	}
+
+	return resp, nil
+}
+
+// PlantChangeStrain is used in the request body to change the Strain of a Plant.
+// See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_changestrains.POST
+type PlantChangeStrain struct {
+	Id         *int    `json:"Id"`
+	Label      *string `json:"Label"`
+	StrainName string  `json:"StrainName"`
+	ActualDate string  `json:"ActualDate"`
+}
+
+// PostPlantsChangeStrains changes the Strains of Plants within Metrc.
+// See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_changestrains.POST
+func (m *Metrc) PostPlantsChangeStrains(changes []PlantChangeStrain, licenseNumber string) ([]byte, error) {
+	var optLabels []*string
+	for _, c := range changes {
+		optLabels = append(optLabels, c.Label)
+	}
+	err := m.checkOptionalLabels(optLabels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "change plant strains", "CanGrowPlants", "CanUpdatePlantStrains")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := "plants/v1/changestrains"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(changes)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal strain changes: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed posting strain changes: %s", err)
+	}
+
+	return resp, nil
+}
+
+// PlantWaste is used in the request body to record waste from a Plant without destroying it.
+// See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_waste.POST
+type PlantWaste struct {
+	Id                     *int    `json:"Id"`
+	Label                  *string `json:"Label"`
+	WasteMethodName        string  `json:"WasteMethodName"`
+	WasteMaterialMixed     string  `json:"WasteMaterialMixed"`
+	WasteWeight            float64 `json:"WasteWeight"`
+	WasteUnitOfMeasureName string  `json:"WasteUnitOfMeasureName"`
+	WasteReasonName        string  `json:"WasteReasonName"`
+	ReasonNote             string  `json:"ReasonNote"`
+	ActualDate             string  `json:"ActualDate"`
+}
+
+// PostPlantsWaste records waste for Plants.
+// See: https://testing-api-ca.metrc.com/Documentation#Plants.post_plants_v1_waste.POST
+func (m *Metrc) PostPlantsWaste(wastes []PlantWaste, licenseNumber string) ([]byte, error) {
+	var optLabels []*string
+	for _, w := range wastes {
+		optLabels = append(optLabels, w.Label)
+	}
+	err := m.checkOptionalLabels(optLabels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkCapabilities(licenseNumber, "record plant waste", "CanGrowPlants")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := "plants/v1/waste"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(wastes)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal plant waste: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed posting plant waste: %s", err)
+	}
+
+	return resp, nil
+}
@@ -92,3 +92,48 @@ func TestPlantsManicure_Integration(t *testing.T) {
 func TestPlantsHarvest_Integration(t *testing.T) {
 	// TODO: Implement.
 }
+
+func TestPlantsReplaceTags_Integration(t *testing.T) {
+	// TODO: Implement.
+}
+
+func TestPlantsChangeStrains_Integration(t *testing.T) {
+	// TODO: Implement.
+}
+
+func TestPlantsWaste_Integration(t *testing.T) {
+	// TODO: Implement.
+}
+
+func TestPlantsSublocations(t *testing.T) {
+	fc := makeFakeClient(map[string]string{})
+	fm := &Metrc{Client: fc}
+	shelf := "Shelf 1"
+	label := "1A4FF0100000022000000001"
+	endpoint := func(path string) string { return fmt.Sprintf("%s?licenseNumber=%s", path, licenseNumber) }
+
+	_, err := fm.PostPlantsMovePlants([]PlantMovePost{{Label: &label, Location: "Flower Room", Sublocation: &shelf}}, licenseNumber)
+	assert.NoError(t, err)
+	assert.Contains(t, string(fc.bodies[endpoint("plants/v1/moveplants")]), `"Sublocation":"Shelf 1"`)
+
+	_, err = fm.PostPlantsChangeGrowthPhases([]PlantChangeGrowthPhase{{Label: &label, GrowthPhase: "Flowering", NewLocation: "Flower Room", NewSublocation: &shelf}}, licenseNumber)
+	assert.NoError(t, err)
+	assert.Contains(t, string(fc.bodies[endpoint("plants/v1/changegrowthphases")]), `"NewSublocation":"Shelf 1"`)
+
+	_, err = fm.PostPlantsCreatePlantings([]PlantCreatePlanting{{PlantLabel: &label, PlantBatchName: "Batch", SublocationName: &shelf}}, licenseNumber)
+	assert.NoError(t, err)
+	assert.Contains(t, string(fc.bodies[endpoint("plants/v1/create/plantings")]), `"SublocationName":"Shelf 1"`)
+
+	_, err = fm.PostPlantsManicure([]PlantManicure{{Plant: label, DryingLocation: "Drying Room", DryingSublocation: &shelf}}, licenseNumber)
+	assert.NoError(t, err)
+	assert.Contains(t, string(fc.bodies[endpoint("plants/v1/manicureplants")]), `"DryingSublocation":"Shelf 1"`)
+
+	_, err = fm.PostPlantsHarvest([]PlantHarvest{{Plant: label, DryingLocation: "Drying Room", DryingSublocation: &shelf}}, licenseNumber)
+	assert.NoError(t, err)
+	assert.Contains(t, string(fc.bodies[endpoint("plants/v1/harvestplants")]), `"DryingSublocation":"Shelf 1"`)
+
+	// Sublocations are omitted when they are not set.
+	_, err = fm.PostPlantsHarvest([]PlantHarvest{{Plant: label, DryingLocation: "Drying Room"}}, licenseNumber)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(fc.bodies[endpoint("plants/v1/harvestplants")]), "Sublocation")
+}
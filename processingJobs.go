@@ -0,0 +1,250 @@
+package metrc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ProcessingJobType represents a type of processing job, e.g. extraction or infusion.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.get_processing_v1_jobtypes_active.GET
+type ProcessingJobType struct {
+	Id              int      `json:"Id"`
+	Name            string   `json:"Name"`
+	CategoryName    string   `json:"CategoryName"`
+	Description     string   `json:"Description"`
+	ProcessingSteps string   `json:"ProcessingSteps"`
+	Attributes      []string `json:"Attributes"`
+}
+
+// ProcessingJob represents a processing job in Metrc.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.get_processing_v1_{id}.GET
+type ProcessingJob struct {
+	Id                           int                    `json:"Id"`
+	JobName                      string                 `json:"JobName"`
+	JobTypeName                  string                 `json:"JobTypeName"`
+	JobTypeId                    int                    `json:"JobTypeId"`
+	StartDate                    string                 `json:"StartDate"`
+	FinishedDate                 *string                `json:"FinishedDate"`
+	FinishNote                   *string                `json:"FinishNote"`
+	IsFinished                   bool                   `json:"IsFinished"`
+	TotalQuantity                float64                `json:"TotalQuantity"`
+	TotalUnitOfMeasureName       string                 `json:"TotalUnitOfMeasureName"`
+	TotalCountWaste              *float64               `json:"TotalCountWaste"`
+	WasteCountUnitOfMeasureName  *string                `json:"WasteCountUnitOfMeasureName"`
+	TotalWeightWaste             *float64               `json:"TotalWeightWaste"`
+	WasteWeightUnitOfMeasureName *string                `json:"WasteWeightUnitOfMeasureName"`
+	Packages                     []ProcessingJobPackage `json:"Packages"`
+	LastModified                 string                 `json:"LastModified"`
+}
+
+// ProcessingJobPackage represents a source package consumed by a processing job.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.post_processing_v1_start.POST
+type ProcessingJobPackage struct {
+	Label         string  `json:"Label"`
+	Quantity      float64 `json:"Quantity"`
+	UnitOfMeasure string  `json:"UnitOfMeasure"`
+}
+
+// GetProcessingJobsById gets the processing job with the specified ID.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.get_processing_v1_{id}.GET
+func (m *Metrc) GetProcessingJobsById(id int, licenseNumber string) (ProcessingJob, error) {
+	endpoint := fmt.Sprintf("processing/v1/%d", id)
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	var pj ProcessingJob
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return pj, fmt.Errorf("could not get processing job by id response: %s", err)
+	}
+
+	err = json.Unmarshal(responseBody, &pj)
+	if err != nil {
+		return pj, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return pj, nil
+}
+
+// helper method to get processing jobs with the "status" endpoint
+func (m *Metrc) getProcessingJobsByStatus(status string, licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) ([]ProcessingJob, error) {
+	endpoint := fmt.Sprintf("processing/v1/%s", status)
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+	if lastModifiedStart != nil {
+		endpoint += fmt.Sprintf("&lastModifiedStart=%s", *lastModifiedStart)
+	}
+	if lastModifiedEnd != nil {
+		endpoint += fmt.Sprintf("&lastModifiedEnd=%s", *lastModifiedEnd)
+	}
+
+	var pj []ProcessingJob
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return pj, fmt.Errorf("could not get processing jobs by status response: %s", err)
+	}
+
+	err = json.Unmarshal(responseBody, &pj)
+	if err != nil {
+		return pj, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return pj, nil
+}
+
+// GetProcessingJobsActive gets all active processing jobs.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.get_processing_v1_active.GET
+func (m *Metrc) GetProcessingJobsActive(licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) ([]ProcessingJob, error) {
+	jobs, err := m.getProcessingJobsByStatus("active", licenseNumber, lastModifiedStart, lastModifiedEnd)
+	if err != nil {
+		return []ProcessingJob{}, fmt.Errorf("could not get active processing jobs: %s", err)
+	}
+
+	return jobs, nil
+}
+
+// GetProcessingJobsInactive gets all inactive processing jobs.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.get_processing_v1_inactive.GET
+func (m *Metrc) GetProcessingJobsInactive(licenseNumber string, lastModifiedStart *string, lastModifiedEnd *string) ([]ProcessingJob, error) {
+	jobs, err := m.getProcessingJobsByStatus("inactive", licenseNumber, lastModifiedStart, lastModifiedEnd)
+	if err != nil {
+		return []ProcessingJob{}, fmt.Errorf("could not get inactive processing jobs: %s", err)
+	}
+
+	return jobs, nil
+}
+
+// GetProcessingJobTypesActive gets the active processing job types.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.get_processing_v1_jobtypes_active.GET
+func (m *Metrc) GetProcessingJobTypesActive(licenseNumber string) ([]ProcessingJobType, error) {
+	endpoint := "processing/v1/jobtypes/active"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	var types []ProcessingJobType
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return types, fmt.Errorf("could not get processing job types response: %s", err)
+	}
+
+	err = json.Unmarshal(responseBody, &types)
+	if err != nil {
+		return types, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return types, nil
+}
+
+// ProcessingJobStart is used in the request body to start a processing job from source packages.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.post_processing_v1_start.POST
+type ProcessingJobStart struct {
+	JobName   string                 `json:"JobName"`
+	JobType   string                 `json:"JobType"`
+	StartDate string                 `json:"StartDate"`
+	Packages  []ProcessingJobPackage `json:"Packages"`
+}
+
+// PostProcessingJobsStart starts processing jobs.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.post_processing_v1_start.POST
+func (m *Metrc) PostProcessingJobsStart(jobs []ProcessingJobStart, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, j := range jobs {
+		for _, p := range j.Packages {
+			labels = append(labels, p.Label)
+		}
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := "processing/v1/start"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(jobs)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal start processing jobs: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed posting start processing jobs: %s", err)
+	}
+
+	return resp, nil
+}
+
+// ProcessingJobFinish is used in the request body to finish a processing job.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.put_processing_v1_finish.PUT
+type ProcessingJobFinish struct {
+	Id                       int      `json:"Id"`
+	FinishDate               string   `json:"FinishDate"`
+	FinishNote               *string  `json:"FinishNote"`
+	TotalCountWaste          *float64 `json:"TotalCountWaste"`
+	WasteCountUnitOfMeasure  *string  `json:"WasteCountUnitOfMeasure"`
+	TotalWeightWaste         *float64 `json:"TotalWeightWaste"`
+	WasteWeightUnitOfMeasure *string  `json:"WasteWeightUnitOfMeasure"`
+}
+
+// PutProcessingJobsFinish finishes processing jobs.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.put_processing_v1_finish.PUT
+func (m *Metrc) PutProcessingJobsFinish(jobs []ProcessingJobFinish, licenseNumber string) ([]byte, error) {
+	endpoint := "processing/v1/finish"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(jobs)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal finish processing jobs: %s", err)
+	}
+
+	resp, err := m.Client.Put(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed putting finish processing jobs: %s", err)
+	}
+
+	return resp, nil
+}
+
+// ProcessingJobCreatePackage is used in the request body to create a package from a processing job.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.post_processing_v1_createpackages.POST
+type ProcessingJobCreatePackage struct {
+	JobName               string  `json:"JobName"`
+	Tag                   string  `json:"Tag"`
+	Location              *string `json:"Location"`
+	Sublocation           *string `json:"Sublocation,omitempty"`
+	Item                  string  `json:"Item"`
+	Quantity              float64 `json:"Quantity"`
+	UnitOfMeasure         string  `json:"UnitOfMeasure"`
+	Note                  string  `json:"Note"`
+	PatientLicenseNumber  string  `json:"PatientLicenseNumber"`
+	IsProductionBatch     bool    `json:"IsProductionBatch"`
+	ProductionBatchNumber *int    `json:"ProductionBatchNumber"`
+	IsFinishedGood        bool    `json:"IsFinishedGood"`
+	FinishDate            *string `json:"FinishDate"`
+	PackageDate           string  `json:"PackageDate"`
+}
+
+// PostProcessingJobsCreatePackages creates packages from processing jobs.
+// See: https://testing-api-ca.metrc.com/Documentation#ProcessingJob.post_processing_v1_createpackages.POST
+func (m *Metrc) PostProcessingJobsCreatePackages(packages []ProcessingJobCreatePackage, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, p := range packages {
+		labels = append(labels, p.Tag)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	endpoint := "processing/v1/createpackages"
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	body, err := json.Marshal(packages)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal processing job packages: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed posting processing job packages: %s", err)
+	}
+
+	return resp, nil
+}
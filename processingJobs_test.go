@@ -0,0 +1,30 @@
+package metrc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessingJobsActive_Integration(t *testing.T) {
+	_, err := m.GetProcessingJobsActive(licenseNumber, nil, nil)
+	assert.NoError(t, err)
+}
+
+func TestProcessingJobsInactive_Integration(t *testing.T) {
+	_, err := m.GetProcessingJobsInactive(licenseNumber, nil, nil)
+	assert.NoError(t, err)
+}
+
+func TestProcessingJobTypesActive_Integration(t *testing.T) {
+	_, err := m.GetProcessingJobTypesActive(licenseNumber)
+	assert.NoError(t, err)
+}
+
+func TestProcessingJobsStartFinish_Integration(t *testing.T) {
+	// TODO: Implement once Package works.
+}
+
+func TestProcessingJobsCreatePackages_Integration(t *testing.T) {
+	// TODO: Implement once Package works.
+}
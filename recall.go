@@ -0,0 +1,292 @@
+package metrc
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RecallPackage is an active or on hold Package affected by a recall.
+type RecallPackage struct {
+	Id                int
+	Label             string
+	Quantity          float64
+	UnitOfMeasureName string
+	LocationName      string
+	IsOnHold          bool
+}
+
+// RecallTransfer is a Package on an outgoing transfer affected by a recall.
+type RecallTransfer struct {
+	TransferId                     int
+	ManifestNumber                 string
+	DeliveryId                     int
+	RecipientFacilityLicenseNumber string
+	RecipientFacilityName          string
+	PackageLabel                   string
+}
+
+// RecallSale is a sales receipt containing Packages affected by a recall.
+type RecallSale struct {
+	ReceiptId     int
+	ReceiptNumber string
+	SalesDateTime string
+	PackageLabels []string
+}
+
+// RecallImpact is everything downstream of a recalled harvest or Package.
+type RecallImpact struct {
+	Source GenealogyNode
+
+	// Labels are every affected Package, including ones no longer active.
+	Labels    []string
+	Packages  []RecallPackage
+	Transfers []RecallTransfer
+	Sales     []RecallSale
+
+	// Genealogy links the source to every affected Package, transfer Package, and sale.
+	Genealogy *Genealogy
+}
+
+// AnalyzeRecall finds everything affected by a recalled harvest (`HarvestNode`) or Package (`PackageNode`) at a license:
+// every derived Package that is still active or on hold, with its location, every outgoing transfer modified between start
+// and end that carries an affected Package, and every active sales receipt sold between start and end that sells one.
+//
+// Packages made from a harvest are found among the active and on hold Packages, and the inactive Packages modified between
+// start and end, so Packages that have since been sold out and finished still lead to the receipts and transfers that moved them.
+//
+// The window bounds the cost: besides listing the Packages, it makes two calls per derived Package to walk the genealogy,
+// one per transfer and one per delivery in the window, and one per receipt in the window that is listed without its transactions.
+func (m *Metrc) AnalyzeRecall(source GenealogyNode, licenseNumber string, start string, end string) (RecallImpact, error) {
+	impact := RecallImpact{Source: source}
+	if source.Kind != GenealogyHarvest && source.Kind != GenealogyPackage {
+		return impact, fmt.Errorf("can only recall a harvest or package, got %s", source)
+	}
+
+	active, err := m.GetPackagesActive(licenseNumber, nil, nil)
+	if err != nil {
+		return impact, fmt.Errorf("could not get active packages: %s", err)
+	}
+	onHold, err := m.GetPackagesOnHold(licenseNumber, nil, nil)
+	if err != nil {
+		return impact, fmt.Errorf("could not get on hold packages: %s", err)
+	}
+	inventory := map[string]PackageGet{}
+	for _, p := range append(active, onHold...) {
+		inventory[p.Label] = p
+	}
+
+	// Packages made directly from the source start the walk through derived Packages.
+	var seeds []string
+	if source.Kind == GenealogyPackage {
+		seeds = append(seeds, source.Name)
+	} else {
+		inactive, err := m.GetPackagesInactive(licenseNumber, &start, &end)
+		if err != nil {
+			return impact, fmt.Errorf("could not get inactive packages: %s", err)
+		}
+
+		seen := map[string]bool{}
+		for _, p := range append(append(active, onHold...), inactive...) {
+			for _, h := range p.SourceHarvestNameList() {
+				if h == source.Name && !seen[p.Label] {
+					seen[p.Label] = true
+					seeds = append(seeds, p.Label)
+				}
+			}
+		}
+		sort.Strings(seeds)
+	}
+
+	g := MakeGenealogy()
+	g.AddNode(source)
+	if len(seeds) > 0 {
+		g, err = m.BuildGenealogyDescendants(seeds, licenseNumber, GenealogyScope{})
+		if err != nil {
+			return impact, fmt.Errorf("could not trace affected packages: %s", err)
+		}
+	}
+	for _, p := range inventory {
+		g.AddPackage(p)
+	}
+	impact.Genealogy = g
+
+	affected := map[string]bool{}
+	if source.Kind == GenealogyPackage {
+		affected[source.Name] = true
+	}
+	for _, n := range g.Descendants(source) {
+		if n.Kind == GenealogyPackage {
+			affected[n.Name] = true
+		}
+	}
+
+	impact.Transfers, err = m.recallTransfers(source, affected, g, licenseNumber, start, end)
+	if err != nil {
+		return impact, err
+	}
+
+	impact.Sales, err = m.recallSales(affected, g, licenseNumber, start, end)
+	if err != nil {
+		return impact, err
+	}
+
+	for label := range affected {
+		impact.Labels = append(impact.Labels, label)
+
+		p, ok := inventory[label]
+		if !ok {
+			continue
+		}
+		rp := RecallPackage{
+			Id:                p.Id,
+			Label:             p.Label,
+			Quantity:          p.Quantity,
+			UnitOfMeasureName: p.UnitOfMeasureName,
+			IsOnHold:          p.IsOnHold,
+		}
+		if p.LocationName != nil {
+			rp.LocationName = *p.LocationName
+		}
+		impact.Packages = append(impact.Packages, rp)
+	}
+	sort.Strings(impact.Labels)
+	sort.Slice(impact.Packages, func(i, j int) bool { return impact.Packages[i].Label < impact.Packages[j].Label })
+
+	return impact, nil
+}
+
+// recallTransfers finds the Packages on outgoing transfers modified between start and end that are affected, or were made from the source.
+// Affected transfer Packages are added to affected and g.
+func (m *Metrc) recallTransfers(source GenealogyNode, affected map[string]bool, g *Genealogy, licenseNumber string, start string, end string) ([]RecallTransfer, error) {
+	transfers, err := m.GetTransfersOutgoing(licenseNumber, &start, &end)
+	if err != nil {
+		return []RecallTransfer{}, fmt.Errorf("could not get outgoing transfers: %s", err)
+	}
+
+	var rts []RecallTransfer
+	for _, t := range transfers {
+		deliveries, err := m.GetTransfersDeliveriesById(t.Id)
+		if err != nil {
+			return rts, fmt.Errorf("could not get deliveries of transfer %d: %s", t.Id, err)
+		}
+
+		for _, d := range deliveries {
+			packages, err := m.GetTransfersDeliveryPackages(d.Id)
+			if err != nil {
+				return rts, fmt.Errorf("could not get packages of delivery %d: %s", d.Id, err)
+			}
+
+			for _, p := range packages {
+				if !transferPackageAffected(p, source, affected) {
+					continue
+				}
+
+				affected[p.PackageLabel] = true
+				g.AddTransferPackage(p)
+				rt := RecallTransfer{
+					TransferId:                     t.Id,
+					DeliveryId:                     d.Id,
+					RecipientFacilityLicenseNumber: d.RecipientFacilityLicenseNumber,
+					RecipientFacilityName:          d.RecipientFacilityName,
+					PackageLabel:                   p.PackageLabel,
+				}
+				if t.ManifestNumber != nil {
+					rt.ManifestNumber = *t.ManifestNumber
+				}
+				rts = append(rts, rt)
+			}
+		}
+	}
+
+	return rts, nil
+}
+
+func transferPackageAffected(p TransferDeliveryPackage, source GenealogyNode, affected map[string]bool) bool {
+	if affected[p.PackageLabel] {
+		return true
+	}
+	for _, l := range splitNames(p.SourcePackageLabels) {
+		if affected[l] {
+			return true
+		}
+	}
+	if source.Kind == GenealogyHarvest {
+		for _, h := range splitNames(p.SourceHarvestNames) {
+			if h == source.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recallSales finds the active receipts sold between start and end that sell affected Packages, adding them to g.
+func (m *Metrc) recallSales(affected map[string]bool, g *Genealogy, licenseNumber string, start string, end string) ([]RecallSale, error) {
+	receipts, err := m.salesReceiptsSelling(affected, licenseNumber, start, end)
+	if err != nil {
+		return []RecallSale{}, err
+	}
+
+	var rss []RecallSale
+	for _, r := range receipts {
+		g.AddSalesReceipt(r)
+		rs := RecallSale{
+			ReceiptId:     r.Id,
+			SalesDateTime: r.SalesDateTime,
+		}
+		if r.ReceiptNumber != nil {
+			rs.ReceiptNumber = *r.ReceiptNumber
+		}
+		for _, t := range r.Transactions {
+			if affected[t.PackageLabel] {
+				rs.PackageLabels = append(rs.PackageLabels, t.PackageLabel)
+			}
+		}
+		rss = append(rss, rs)
+	}
+
+	return rss, nil
+}
+
+// RecallPlan is the bulk action to contain a recall.
+// Metrc does not let a licensee place Packages on hold through the API, so Packages with stock left are marked with a
+// recall note for the state to hold and for staff to pull, and empty Packages are finished.
+type RecallPlan struct {
+	Notes    []PackageNote
+	Finishes []PackageFinish
+}
+
+// Plan proposes the action plan for the affected Packages. Packages already on hold are left as they are.
+func (ri RecallImpact) Plan(note string, finishDate string) RecallPlan {
+	var plan RecallPlan
+	for _, p := range ri.Packages {
+		switch {
+		case p.IsOnHold:
+			continue
+		case p.Quantity <= 0:
+			plan.Finishes = append(plan.Finishes, PackageFinish{Label: p.Label, ActualDate: finishDate})
+		default:
+			plan.Notes = append(plan.Notes, PackageNote{Label: p.Label, Note: note})
+		}
+	}
+	return plan
+}
+
+// ExecuteRecallPlan posts a plan with `PutPackagesChangeNote` and `PostPackagesFinish`.
+func (m *Metrc) ExecuteRecallPlan(plan RecallPlan, licenseNumber string) error {
+	if len(plan.Notes) > 0 {
+		_, err := m.PutPackagesChangeNote(plan.Notes, licenseNumber)
+		if err != nil {
+			return fmt.Errorf("could not note recalled packages: %s", err)
+		}
+	}
+
+	if len(plan.Finishes) > 0 {
+		_, err := m.PostPackagesFinish(plan.Finishes, licenseNumber)
+		if err != nil {
+			return fmt.Errorf("could not finish recalled packages: %s", err)
+		}
+	}
+
+	return nil
+}
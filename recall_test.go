@@ -0,0 +1,98 @@
+package metrc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecallAnalyze(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"packages/v1/active": `[
+			{"Id": 1, "Label": "PKG-1", "Quantity": 10, "UnitOfMeasureName": "Grams", "LocationName": "Vault", "SourceHarvestNames": "Harvest A"},
+			{"Id": 2, "Label": "PKG-2", "Quantity": 0, "UnitOfMeasureName": "Grams"},
+			{"Id": 9, "Label": "PKG-9", "Quantity": 5, "UnitOfMeasureName": "Grams", "SourceHarvestNames": "Harvest B"}
+		]`,
+		"packages/v1/onhold":         `[{"Id": 4, "Label": "PKG-4", "Quantity": 3, "IsOnHold": true, "SourceHarvestNames": "Harvest A"}]`,
+		"packages/v1/inactive":       `[]`,
+		"packages/v1/PKG-1":          `{"Id": 1, "Label": "PKG-1"}`,
+		"packages/v1/PKG-2":          `{"Id": 2, "Label": "PKG-2"}`,
+		"packages/v1/PKG-3":          `{"Id": 3, "Label": "PKG-3"}`,
+		"packages/v1/PKG-4":          `{"Id": 4, "Label": "PKG-4"}`,
+		"packages/v1/1/children":     `[{"PackageLabel": "PKG-2"}]`,
+		"packages/v1/2/children":     `[{"PackageLabel": "PKG-3"}]`,
+		"packages/v1/3/children":     `[]`,
+		"packages/v1/4/children":     `[]`,
+		"transfers/v1/outgoing":      `[{"Id": 10, "ManifestNumber": "M-10"}]`,
+		"transfers/v1/10/deliveries": `[{"Id": 20, "RecipientFacilityLicenseNumber": "LIC-9", "RecipientFacilityName": "Shop"}]`,
+		"transfers/v1/delivery/20/packages": `[
+			{"PackageLabel": "PKG-3"},
+			{"PackageLabel": "PKG-5", "SourceHarvestNames": "Harvest A"},
+			{"PackageLabel": "PKG-8", "SourceHarvestNames": "Harvest B"}
+		]`,
+		"sales/v1/receipts/active": `[{"Id": 30, "TotalPackages": 1}, {"Id": 31, "Transactions": [{"PackageLabel": "PKG-9"}]}]`,
+		"sales/v1/receipts/30":     `{"Id": 30, "ReceiptNumber": "R-30", "Transactions": [{"PackageLabel": "PKG-2"}]}`,
+	})
+	m := &Metrc{Client: fc}
+
+	impact, err := m.AnalyzeRecall(HarvestNode("Harvest A"), licenseNumber, "2026-10-01", "2026-10-19")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PKG-1", "PKG-2", "PKG-3", "PKG-4", "PKG-5"}, impact.Labels)
+	assert.Equal(t, []RecallPackage{
+		{Id: 1, Label: "PKG-1", Quantity: 10, UnitOfMeasureName: "Grams", LocationName: "Vault"},
+		{Id: 2, Label: "PKG-2", UnitOfMeasureName: "Grams"},
+		{Id: 4, Label: "PKG-4", Quantity: 3, IsOnHold: true},
+	}, impact.Packages)
+	assert.Equal(t, []RecallTransfer{
+		{TransferId: 10, ManifestNumber: "M-10", DeliveryId: 20, RecipientFacilityLicenseNumber: "LIC-9", RecipientFacilityName: "Shop", PackageLabel: "PKG-3"},
+		{TransferId: 10, ManifestNumber: "M-10", DeliveryId: 20, RecipientFacilityLicenseNumber: "LIC-9", RecipientFacilityName: "Shop", PackageLabel: "PKG-5"},
+	}, impact.Transfers)
+	assert.Equal(t, []RecallSale{{ReceiptId: 30, ReceiptNumber: "R-30", PackageLabels: []string{"PKG-2"}}}, impact.Sales)
+	assert.Contains(t, impact.Genealogy.Descendants(HarvestNode("Harvest A")), SaleNode("30"))
+	assert.Contains(t, fc.gets, "transfers/v1/outgoing?licenseNumber="+licenseNumber+"&lastModifiedStart=2026-10-01&lastModifiedEnd=2026-10-19")
+	assert.Contains(t, fc.gets, "sales/v1/receipts/active?licenseNumber="+licenseNumber+"&salesDateEnd=2026-10-19&salesDateStart=2026-10-01")
+
+	plan := impact.Plan("Recall of Harvest A", "2026-10-19")
+	assert.Equal(t, []PackageNote{{Label: "PKG-1", Note: "Recall of Harvest A"}}, plan.Notes)
+	assert.Equal(t, []PackageFinish{{Label: "PKG-2", ActualDate: "2026-10-19"}}, plan.Finishes)
+
+	assert.NoError(t, m.ExecuteRecallPlan(plan, licenseNumber))
+	var notes []PackageNote
+	assert.NoError(t, json.Unmarshal(fc.bodies["packages/v1/change/note?licenseNumber="+licenseNumber], &notes))
+	assert.Equal(t, plan.Notes, notes)
+	var finishes []PackageFinish
+	assert.NoError(t, json.Unmarshal(fc.bodies["packages/v1/finish?licenseNumber="+licenseNumber], &finishes))
+	assert.Equal(t, plan.Finishes, finishes)
+}
+
+func TestRecallAnalyzeFinishedPackages(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"packages/v1/active":     `[]`,
+		"packages/v1/onhold":     `[]`,
+		"packages/v1/inactive":   `[{"Id": 6, "Label": "PKG-6", "SourceHarvestNames": "Harvest A"}]`,
+		"packages/v1/PKG-6":      `{"Id": 6, "Label": "PKG-6", "SourceHarvestNames": "Harvest A"}`,
+		"packages/v1/PKG-7":      `{"Id": 7, "Label": "PKG-7"}`,
+		"packages/v1/6/children": `[{"PackageLabel": "PKG-7"}]`,
+		"packages/v1/7/children": `[]`,
+		"transfers/v1/outgoing":  `[]`,
+		"sales/v1/receipts/active": `[
+			{"Id": 32, "ReceiptNumber": "R-32", "Transactions": [{"PackageLabel": "PKG-7"}]},
+			{"Id": 33, "ReceiptNumber": "R-33", "Transactions": [{"PackageLabel": "PKG-9"}]}
+		]`,
+	})
+	m := &Metrc{Client: fc}
+
+	// The Packages made from the harvest have been sold out and finished, but the receipt that sold them is still found.
+	impact, err := m.AnalyzeRecall(HarvestNode("Harvest A"), licenseNumber, "2026-10-01", "2026-10-19")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PKG-6", "PKG-7"}, impact.Labels)
+	assert.Empty(t, impact.Packages)
+	assert.Equal(t, []RecallSale{{ReceiptId: 32, ReceiptNumber: "R-32", PackageLabels: []string{"PKG-7"}}}, impact.Sales)
+	assert.Contains(t, fc.gets, "packages/v1/inactive?lastModifiedEnd=2026-10-19&lastModifiedStart=2026-10-01&licenseNumber="+licenseNumber)
+}
+
+func TestRecallAnalyzeRejectsPlants(t *testing.T) {
+	_, err := (&Metrc{Client: makeFakeClient(nil)}).AnalyzeRecall(PlantNode("PLANT-1"), licenseNumber, "2026-10-01", "2026-10-19")
+	assert.Error(t, err)
+}
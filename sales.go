@@ -8,19 +8,35 @@ import (
 // SalesReceiptGet represents a receipt in the responses to `GET sales/v1/receipt` endpoints.
 // See: https://api-ca.metrc.com/Documentation/#Sales.get_sales_v1_receipts_active
 type SalesReceiptGet struct {
-	Id                   int                   `json:"Id"`
-	ReceiptNumber        *string               `json:"ReceiptNumber"`
-	SalesDateTime        string                `json:"SalesDateTime"`
-	SalesCustomerType    string                `json:"SalesCustomerType"`
-	PatientLicenseNumber *string               `json:"PatientLicenseNumber"`
-	TotalPackages        int                   `json:"TotalPackages"`
-	TotalPrice           float64               `json:"TotalPrice"`
-	Transactions         []SalesTransactionGet `json:"Transactions"`
-	IsFinal              bool                  `json:"IsFinal"`
-	ArchivedDate         *string               `json:"ArchivedDate"`
-	RecordedDateTime     string                `json:"RecordedDateTime"`
-	RecordedByUserName   *string               `json:"RecordedByUserName"`
-	LastModified         string                `json:"LastModified"`
+	Id                   int                       `json:"Id"`
+	ReceiptNumber        *string                   `json:"ReceiptNumber"`
+	SalesDateTime        string                    `json:"SalesDateTime"`
+	SalesCustomerType    string                    `json:"SalesCustomerType"`
+	PatientLicenseNumber *string                   `json:"PatientLicenseNumber"`
+	TotalPackages        int                       `json:"TotalPackages"`
+	TotalPrice           float64                   `json:"TotalPrice"`
+	Transactions         []SalesReceiptTransaction `json:"Transactions"`
+	IsFinal              bool                      `json:"IsFinal"`
+	ArchivedDate         *string                   `json:"ArchivedDate"`
+	RecordedDateTime     string                    `json:"RecordedDateTime"`
+	RecordedByUserName   *string                   `json:"RecordedByUserName"`
+	LastModified         string                    `json:"LastModified"`
+}
+
+// SalesReceiptTransaction represents a Package sold on a receipt, in the responses to `GET sales/v1/receipts`.
+// See: https://api-ca.metrc.com/Documentation/#Sales.get_sales_v1_receipts_{id}
+type SalesReceiptTransaction struct {
+	PackageId                 int     `json:"PackageId"`
+	PackageLabel              string  `json:"PackageLabel"`
+	ProductName               string  `json:"ProductName"`
+	ProductCategoryName       string  `json:"ProductCategoryName"`
+	QuantitySold              float64 `json:"QuantitySold"`
+	UnitOfMeasureName         string  `json:"UnitOfMeasureName"`
+	UnitOfMeasureAbbreviation string  `json:"UnitOfMeasureAbbreviation"`
+	TotalPrice                float64 `json:"TotalPrice"`
+	ArchivedDate              *string `json:"ArchivedDate"`
+	RecordedDateTime          string  `json:"RecordedDateTime"`
+	LastModified              string  `json:"LastModified"`
 }
 
 // SalesReceiptPost represents a receipt in the request to `POST sales/v1/receipts`.
@@ -53,6 +69,17 @@ type SalesTransactionPost struct {
 	TotalAmount    float64 `json:"TotalAmount"`
 }
 
+// salesReceiptLabels returns the package labels referenced by receipts.
+func salesReceiptLabels(receipts []SalesReceiptPost) []string {
+	var labels []string
+	for _, r := range receipts {
+		for _, t := range r.Transactions {
+			labels = append(labels, t.PackageLabel)
+		}
+	}
+	return labels
+}
+
 // GetSalesCustomerTypes gets the customer types for Sales.
 // See: https://api-ca.metrc.com/Documentation/#Sales.get_sales_v1_customertypes
 func (m *Metrc) GetSalesCustomerTypes() ([]string, error) {
@@ -74,40 +101,15 @@ func (m *Metrc) GetSalesCustomerTypes() ([]string, error) {
 // GetSalesReceiptsActive gets all active receipts.
 // See: https://api-ca.metrc.com/Documentation/#Sales.get_sales_v1_receipts_active
 func (m *Metrc) GetSalesReceiptsActive(licenseNumber string, salesDateStart *string, salesDateEnd *string, lastModifiedStart *string, lastModifiedEnd *string) ([]SalesReceiptGet, error) {
-	// Metrc API specification says that sales date range and last modified range cannot both be specified.
-	hasSalesDate := (salesDateStart != nil || salesDateEnd != nil)
-	hasLastModified := (lastModifiedStart != nil || lastModifiedEnd != nil)
-	if hasSalesDate && hasLastModified {
-		return []SalesReceiptGet{}, fmt.Errorf("cannot specify both salesDate and lastModified params")
-	}
-
-	// Append non-nil optional parameters.
-	endpoint := fmt.Sprintf("sales/v1/receipts/active?licenseNumber=%s", licenseNumber)
-	if salesDateStart != nil {
-		endpoint += fmt.Sprintf("&salesDateStart=%s", *lastModifiedStart)
-	}
-
-	if salesDateEnd != nil {
-		endpoint += fmt.Sprintf("&salesDateEnd=%s", *lastModifiedEnd)
-	}
-
-	if lastModifiedStart != nil {
-		endpoint += fmt.Sprintf("&lastModifiedStart=%s", *lastModifiedStart)
-	}
-
-	if lastModifiedEnd != nil {
-		endpoint += fmt.Sprintf("&lastModifiedEnd=%s", *lastModifiedEnd)
-	}
-
-	resp, err := m.Client.Get(endpoint)
+	params, err := salesReceiptParams(salesDateStart, salesDateEnd, lastModifiedStart, lastModifiedEnd)
 	if err != nil {
-		return []SalesReceiptGet{}, fmt.Errorf("could not get active receipts from metrc, license number %s: %s", licenseNumber, err)
+		return []SalesReceiptGet{}, err
 	}
 
 	var arr []SalesReceiptGet
-	err = json.Unmarshal(resp, &arr)
+	err = m.getList("sales", "receipts/active", licenseNumber, params, &arr)
 	if err != nil {
-		return arr, fmt.Errorf("could not unmarshal receipts, license number %s: %s", licenseNumber, err)
+		return arr, fmt.Errorf("could not get active receipts from metrc, license number %s: %s", licenseNumber, err)
 	}
 
 	return arr, nil
@@ -116,40 +118,15 @@ func (m *Metrc) GetSalesReceiptsActive(licenseNumber string, salesDateStart *str
 // GetSalesReceiptsInactive gets all inactive receipts.
 // See: https://api-ca.metrc.com/Documentation/#Sales.get_sales_v1_receipts_inactive
 func (m *Metrc) GetSalesReceiptsInactive(licenseNumber string, salesDateStart *string, salesDateEnd *string, lastModifiedStart *string, lastModifiedEnd *string) ([]SalesReceiptGet, error) {
-	// Metrc API specification says that sales date range and last modified range cannot both be specified.
-	hasSalesDate := (salesDateStart != nil || salesDateEnd != nil)
-	hasLastModified := (lastModifiedStart != nil || lastModifiedEnd != nil)
-	if hasSalesDate && hasLastModified {
-		return []SalesReceiptGet{}, fmt.Errorf("cannot specify both salesDate and lastModified params")
-	}
-
-	// Append non-nil optional parameters.
-	endpoint := fmt.Sprintf("sales/v1/receipts/inactive?licenseNumber=%s", licenseNumber)
-	if salesDateStart != nil {
-		endpoint += fmt.Sprintf("&salesDateStart=%s", *lastModifiedStart)
-	}
-
-	if salesDateEnd != nil {
-		endpoint += fmt.Sprintf("&salesDateEnd=%s", *lastModifiedEnd)
-	}
-
-	if lastModifiedStart != nil {
-		endpoint += fmt.Sprintf("&lastModifiedStart=%s", *lastModifiedStart)
-	}
-
-	if lastModifiedEnd != nil {
-		endpoint += fmt.Sprintf("&lastModifiedEnd=%s", *lastModifiedEnd)
-	}
-
-	var srr []SalesReceiptGet
-	resp, err := m.Client.Get(endpoint)
+	params, err := salesReceiptParams(salesDateStart, salesDateEnd, lastModifiedStart, lastModifiedEnd)
 	if err != nil {
-		return srr, fmt.Errorf("could not get active receipts from metrc: %s", err)
+		return []SalesReceiptGet{}, err
 	}
 
-	err = json.Unmarshal(resp, &srr)
+	var srr []SalesReceiptGet
+	err = m.getList("sales", "receipts/inactive", licenseNumber, params, &srr)
 	if err != nil {
-		return srr, fmt.Errorf("could not unmarshal receipts: %s", err)
+		return srr, fmt.Errorf("could not get inactive receipts from metrc: %s", err)
 	}
 
 	return srr, nil
@@ -180,6 +157,17 @@ func (m *Metrc) GetSalesReceiptsById(id int, licenseNumber *string) (SalesReceip
 // PostSalesReceipts posts the sales receipts.
 // See: https://api-ca.metrc.com/Documentation/#Sales.post_sales_v1_receipts
 func (m *Metrc) PostSalesReceipts(receipts []SalesReceiptPost, licenseNumber string) ([]byte, error) {
+	labels := salesReceiptLabels(receipts)
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkSalesCapabilities(receipts, licenseNumber)
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("sales/v1/receipts?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(receipts)
@@ -199,6 +187,17 @@ func (m *Metrc) PostSalesReceipts(receipts []SalesReceiptPost, licenseNumber str
 // The primary difference between this and PostSalesReceipts is that this function requires a non-nil Id.
 // See: https://api-ca.metrc.com/Documentation/#Sales.put_sales_v1_receipts
 func (m *Metrc) PutSalesReceipts(receipts []SalesReceiptPost, licenseNumber string) ([]byte, error) {
+	labels := salesReceiptLabels(receipts)
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = m.checkSalesCapabilities(receipts, licenseNumber)
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("sales/v1/receipts?licenseNumber=%s", licenseNumber)
 
 	body, err := json.Marshal(receipts)
@@ -268,6 +267,15 @@ func (m *Metrc) GetSalesTransactionsByDates(salesDateStart string, salesDateEnd
 // PostSalesTransaction posts new Sales transactions.
 // See: https://api-ca.metrc.com/Documentation/#Sales.post_sales_v1_transactions_{date}
 func (m *Metrc) PostSalesTransactions(transactions []SalesTransactionPost, date string, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, t := range transactions {
+		labels = append(labels, t.PackageLabel)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("sales/v1/transactions/%s?licenseNumber=%s", date, licenseNumber)
 
 	body, err := json.Marshal(transactions)
@@ -286,6 +294,15 @@ func (m *Metrc) PostSalesTransactions(transactions []SalesTransactionPost, date
 // PutSalesTransactions puts new Sales transactions.
 // See: https://api-ca.metrc.com/Documentation/#Sales.put_sales_v1_transactions_{date}
 func (m *Metrc) PutSalesTransactions(transactions []SalesTransactionPost, date string, licenseNumber string) ([]byte, error) {
+	var labels []string
+	for _, t := range transactions {
+		labels = append(labels, t.PackageLabel)
+	}
+	err := m.checkLabels(labels...)
+	if err != nil {
+		return []byte{}, err
+	}
+
 	endpoint := fmt.Sprintf("sales/v1/transactions/%s?licenseNumber=%s", date, licenseNumber)
 
 	body, err := json.Marshal(transactions)
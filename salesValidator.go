@@ -0,0 +1,240 @@
+package metrc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SalesReceiptProblem is a receipt or transaction that Metrc would reject. Transaction is -1 for problems with the receipt itself.
+type SalesReceiptProblem struct {
+	Receipt     int
+	Transaction int
+	Label       string
+	Message     string
+}
+
+func (p SalesReceiptProblem) String() string {
+	if p.Transaction < 0 {
+		return fmt.Sprintf("receipt %d: %s", p.Receipt, p.Message)
+	}
+	return fmt.Sprintf("receipt %d transaction %d (%s): %s", p.Receipt, p.Transaction, p.Label, p.Message)
+}
+
+// SalesReceiptValidationError lists every problem found in a batch of receipts.
+type SalesReceiptValidationError struct {
+	Problems []SalesReceiptProblem
+}
+
+func (e *SalesReceiptValidationError) Error() string {
+	var ps []string
+	for _, p := range e.Problems {
+		ps = append(ps, p.String())
+	}
+	return fmt.Sprintf("invalid sales receipts: %s", strings.Join(ps, "; "))
+}
+
+// salesTestingStates are the `LabTestingState`s of Packages that can be sold.
+var salesTestingStates = map[string]bool{
+	"TestPassed":   true,
+	"RetestPassed": true,
+	"NotRequired":  true,
+}
+
+// SalesReceiptValidator checks receipts against the live inventory and sales requirements of a license before they are posted.
+// Packages are fetched on every validation. Customer types and facility capabilities are fetched the first time they are
+// needed and kept until `Reset`.
+type SalesReceiptValidator struct {
+	Metrc         *Metrc
+	LicenseNumber string
+
+	mu            sync.Mutex
+	customerTypes map[string]bool
+	capabilities  *CapabilityGuard
+}
+
+// MakeSalesReceiptValidator creates a validator for the receipts of a license.
+func MakeSalesReceiptValidator(m *Metrc, licenseNumber string) *SalesReceiptValidator {
+	return &SalesReceiptValidator{
+		Metrc:         m,
+		LicenseNumber: licenseNumber,
+		capabilities:  MakeCapabilityGuard(),
+	}
+}
+
+// Reset forgets the cached customer types and capabilities, so they are fetched again on the next validation.
+func (v *SalesReceiptValidator) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.customerTypes = nil
+	v.capabilities.Reset()
+}
+
+// load fetches the customer types if they are not cached. Must be called with v.mu held.
+func (v *SalesReceiptValidator) load() error {
+	if v.customerTypes != nil {
+		return nil
+	}
+
+	cts, err := v.Metrc.GetSalesCustomerTypes()
+	if err != nil {
+		return fmt.Errorf("could not get customer types: %s", err)
+	}
+
+	v.customerTypes = map[string]bool{}
+	for _, ct := range cts {
+		v.customerTypes[ct] = true
+	}
+	return nil
+}
+
+// packages fetches the active and on hold Packages of the license.
+func (v *SalesReceiptValidator) packages() (map[string]PackageGet, error) {
+	active, err := v.Metrc.GetPackagesActive(v.LicenseNumber, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not get active packages: %s", err)
+	}
+	onHold, err := v.Metrc.GetPackagesOnHold(v.LicenseNumber, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not get on hold packages: %s", err)
+	}
+
+	packages := map[string]PackageGet{}
+	for _, p := range append(active, onHold...) {
+		packages[p.Label] = p
+	}
+	return packages, nil
+}
+
+// Validate checks every receipt and returns a `*SalesReceiptValidationError` listing all of their problems, or nil if there are none.
+// Each customer type must be one of `GetSalesCustomerTypes` that the facility can sell to, with the patient, caregiver, and
+// identification details the facility requires for it. Each transaction must sell from an active Package that is not on hold and
+// has passed testing, in a unit convertible to the Package's, and the receipts together may not sell more of a Package than it has.
+// An error of any other type means the Packages, customer types, or facility could not be fetched.
+func (v *SalesReceiptValidator) Validate(receipts []SalesReceiptPost) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	err := v.load()
+	if err != nil {
+		return err
+	}
+	ft, err := v.capabilities.facilityType(v.Metrc, v.LicenseNumber)
+	if err != nil {
+		return err
+	}
+	packages, err := v.packages()
+	if err != nil {
+		return err
+	}
+
+	var problems []SalesReceiptProblem
+	for ri, r := range receipts {
+		add := func(format string, args ...interface{}) {
+			problems = append(problems, SalesReceiptProblem{Receipt: ri, Transaction: -1, Message: fmt.Sprintf(format, args...)})
+		}
+		v.validateCustomer(r, ft, add)
+		if len(r.Transactions) == 0 {
+			add("has no transactions")
+		}
+
+		for ti, t := range r.Transactions {
+			msg := validateSalesTransaction(t, packages)
+			if msg != "" {
+				problems = append(problems, SalesReceiptProblem{Receipt: ri, Transaction: ti, Label: t.PackageLabel, Message: msg})
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &SalesReceiptValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateCustomer checks the customer type of a receipt and the details the facility requires for it.
+func (v *SalesReceiptValidator) validateCustomer(r SalesReceiptPost, ft FacilitiesFacilityType, add func(format string, args ...interface{})) {
+	if !v.customerTypes[r.SalesCustomerType] {
+		add("%q is not a sales customer type", r.SalesCustomerType)
+		return
+	}
+
+	if c, ok := salesCustomerCapabilities[r.SalesCustomerType]; ok {
+		missing, err := missingCapabilities(ft, []string{c})
+		if err != nil {
+			add("could not check whether license %s can sell to %s customers: %s", v.LicenseNumber, r.SalesCustomerType, err)
+			return
+		}
+		if len(missing) > 0 {
+			add("license %s cannot sell to %s customers, the facility does not have %s", v.LicenseNumber, r.SalesCustomerType, c)
+			return
+		}
+	}
+
+	empty := func(s *string) bool { return s == nil || strings.TrimSpace(*s) == "" }
+	switch r.SalesCustomerType {
+	case "Patient":
+		if ft.SalesRequirePatientNumber && empty(r.PatientLicenseNumber) {
+			add("PatientLicenseNumber is required for Patient sales")
+		}
+	case "Caregiver":
+		if ft.SalesRequireCaregiverNumber && empty(r.CaregiverLicenseNumber) {
+			add("CaregiverLicenseNumber is required for Caregiver sales")
+		}
+		if ft.SalesRequireCaregiverPatientNumber && empty(r.PatientLicenseNumber) {
+			add("PatientLicenseNumber is required for Caregiver sales")
+		}
+	case "ExternalPatient":
+		if ft.SalesRequireExternalPatientNumber && empty(r.PatientLicenseNumber) {
+			add("PatientLicenseNumber is required for ExternalPatient sales")
+		}
+		if ft.SalesRequireExternalPatientIdentificationMethod && empty(r.IdentificationMethod) {
+			add("IdentificationMethod is required for ExternalPatient sales")
+		}
+	}
+}
+
+// validateSalesTransaction checks a transaction against its Package, taking what it sells from the Package's quantity
+// so later transactions see what is left. It returns the problem, or "" if there is none.
+func validateSalesTransaction(t SalesTransactionPost, packages map[string]PackageGet) string {
+	p, ok := packages[t.PackageLabel]
+	switch {
+	case !ok:
+		return "is not an active package"
+	case p.IsOnHold:
+		return "package is on hold"
+	case !salesTestingStates[p.LabTestingState]:
+		return fmt.Sprintf("package has not passed testing, it is %s", p.LabTestingState)
+	case t.Quantity <= 0:
+		return fmt.Sprintf("quantity must be positive, got %g", t.Quantity)
+	}
+
+	// Units Metrc names exactly are not converted, so units the converter does not know can still be sold.
+	quantity := t.Quantity
+	if unit := t.UnitsOfMeasure; unit != "" && unit != p.UnitOfMeasureName && unit != p.UnitOfMeasureAbbreviation {
+		var err error
+		quantity, err = ConvertQuantity(t.Quantity, unit, p.UnitOfMeasureName)
+		if err != nil {
+			return fmt.Sprintf("unit is not compatible with the package: %s", err)
+		}
+	}
+
+	// Tolerate floating point error from conversions.
+	if quantity > p.Quantity+1e-9 {
+		return fmt.Sprintf("sells %g %s but only %g %s is left", quantity, p.UnitOfMeasureName, p.Quantity, p.UnitOfMeasureName)
+	}
+	p.Quantity -= quantity
+	packages[p.Label] = p
+	return ""
+}
+
+// Post validates receipts with `Validate` and posts them with `PostSalesReceipts` if they are all valid.
+func (v *SalesReceiptValidator) Post(receipts []SalesReceiptPost) ([]byte, error) {
+	err := v.Validate(receipts)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return v.Metrc.PostSalesReceipts(receipts, v.LicenseNumber)
+}
@@ -0,0 +1,80 @@
+package metrc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSalesReceiptValidate(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"facilities/v1": `[{"License": {"Number": "RETAIL-1"}, "FacilityType": {
+			"CanSellToConsumers": true, "CanSellToPatients": true, "SalesRequirePatientNumber": true
+		}}]`,
+		"sales/v1/customertypes": `["Consumer", "Patient", "Caregiver", "ExternalPatient"]`,
+		"packages/v1/active": `[
+			{"Label": "PKG-1", "Quantity": 10, "UnitOfMeasureName": "Grams", "UnitOfMeasureAbbreviation": "g", "LabTestingState": "TestPassed"},
+			{"Label": "PKG-2", "Quantity": 5, "UnitOfMeasureName": "Each", "UnitOfMeasureAbbreviation": "ea", "LabTestingState": "TestPassed"},
+			{"Label": "PKG-3", "Quantity": 5, "UnitOfMeasureName": "Each", "LabTestingState": "SubmittedForTesting"},
+			{"Label": "PKG-5", "Quantity": 2, "UnitOfMeasureName": "Packs", "UnitOfMeasureAbbreviation": "pk", "LabTestingState": "TestPassed"}
+		]`,
+		"packages/v1/onhold": `[{"Label": "PKG-4", "Quantity": 5, "UnitOfMeasureName": "Each", "LabTestingState": "TestPassed", "IsOnHold": true}]`,
+	})
+	m := &Metrc{Client: fc}
+	v := MakeSalesReceiptValidator(m, "RETAIL-1")
+
+	valid := []SalesReceiptPost{
+		{SalesCustomerType: "Consumer", Transactions: []SalesTransactionPost{
+			{PackageLabel: "PKG-1", Quantity: 4000, UnitsOfMeasure: "Milligrams"},
+			{PackageLabel: "PKG-2", Quantity: 5, UnitsOfMeasure: "ea"},
+		}},
+		{SalesCustomerType: "Consumer", Transactions: []SalesTransactionPost{
+			{PackageLabel: "PKG-1", Quantity: 6, UnitsOfMeasure: "g"},
+			{PackageLabel: "PKG-5", Quantity: 1, UnitsOfMeasure: "pk"},
+			{PackageLabel: "PKG-5", Quantity: 1, UnitsOfMeasure: "Packs"},
+		}},
+	}
+	assert.NoError(t, v.Validate(valid))
+
+	err := v.Validate([]SalesReceiptPost{
+		{SalesCustomerType: "Consumer", Transactions: []SalesTransactionPost{
+			{PackageLabel: "PKG-1", Quantity: 8, UnitsOfMeasure: "Grams"},
+			{PackageLabel: "PKG-1", Quantity: 3, UnitsOfMeasure: "Grams"},
+			{PackageLabel: "PKG-2", Quantity: 1, UnitsOfMeasure: "Grams"},
+			{PackageLabel: "PKG-3", Quantity: 1, UnitsOfMeasure: "Each"},
+			{PackageLabel: "PKG-4", Quantity: 1, UnitsOfMeasure: "Each"},
+			{PackageLabel: "PKG-9", Quantity: 1, UnitsOfMeasure: "Each"},
+		}},
+		{SalesCustomerType: "Patient", Transactions: []SalesTransactionPost{{PackageLabel: "PKG-2", Quantity: 1}}},
+		{SalesCustomerType: "Caregiver"},
+		{SalesCustomerType: "Tourist", Transactions: []SalesTransactionPost{{PackageLabel: "PKG-2", Quantity: 1}}},
+	})
+	var ve *SalesReceiptValidationError
+	assert.True(t, errors.As(err, &ve))
+	var got []string
+	for _, p := range ve.Problems {
+		got = append(got, p.String())
+	}
+	assert.Equal(t, []string{
+		"receipt 0 transaction 1 (PKG-1): sells 3 Grams but only 2 Grams is left",
+		"receipt 0 transaction 2 (PKG-2): unit is not compatible with the package: cannot convert Grams (WeightBased) to Each (CountBased)",
+		"receipt 0 transaction 3 (PKG-3): package has not passed testing, it is SubmittedForTesting",
+		"receipt 0 transaction 4 (PKG-4): package is on hold",
+		"receipt 0 transaction 5 (PKG-9): is not an active package",
+		"receipt 1: PatientLicenseNumber is required for Patient sales",
+		"receipt 2: license RETAIL-1 cannot sell to Caregiver customers, the facility does not have CanSellToCaregivers",
+		"receipt 2: has no transactions",
+		`receipt 3: "Tourist" is not a sales customer type`,
+	}, got)
+
+	// A capability that cannot be checked is a problem, not a pass.
+	salesCustomerCapabilities["Consumer"] = "CanSellToNobody"
+	err = v.Validate(valid[:1])
+	salesCustomerCapabilities["Consumer"] = "CanSellToConsumers"
+	assert.EqualError(t, err, "invalid sales receipts: receipt 0: could not check whether license RETAIL-1 can sell to Consumer customers: unknown facility capability CanSellToNobody")
+
+	_, err = v.Post(valid[:1])
+	assert.NoError(t, err)
+	assert.Contains(t, fc.bodies, "sales/v1/receipts?licenseNumber=RETAIL-1")
+}
@@ -0,0 +1,134 @@
+package metrc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SublocationGet represents a Sublocation in Metrc in the GET requests, e.g. a shelf or rack within a Location.
+// See: https://api-ca.metrc.com/Documentation/#Sublocations.get_sublocations_v1_{id}
+type SublocationGet struct {
+	Id           int     `json:"Id"`
+	Name         string  `json:"Name"`
+	ArchivedDate *string `json:"ArchivedDate"`
+	LastModified string  `json:"LastModified"`
+}
+
+// SublocationPost represents a Sublocation in Metrc in the POST requests.
+// See: https://api-ca.metrc.com/Documentation/#Sublocations.post_sublocations_v1_create
+type SublocationPost struct {
+	Id   int    `json:"Id,omitempty"`
+	Name string `json:"Name"`
+}
+
+// GetSublocationsById gets sublocations with an ID.
+// See: https://api-ca.metrc.com/Documentation/#Sublocations.get_sublocations_v1_{id}
+func (m *Metrc) GetSublocationsById(id int, licenseNumber *string) (SublocationGet, error) {
+	endpoint := fmt.Sprintf("sublocations/v1/%d", id)
+
+	if licenseNumber != nil {
+		endpoint = fmt.Sprintf("%s?licenseNumber=%s", endpoint, *licenseNumber)
+	}
+
+	var sr SublocationGet
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return sr, fmt.Errorf("could not get sublocations by id response: %s", err)
+	}
+
+	err = json.Unmarshal(responseBody, &sr)
+	if err != nil {
+		return sr, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return sr, nil
+}
+
+// helper method to get sublocations with the "status" endpoint
+func (m *Metrc) getSublocationsByStatus(status string, licenseNumber *string) ([]SublocationGet, error) {
+	endpoint := fmt.Sprintf("sublocations/v1/%s", status)
+
+	if licenseNumber != nil {
+		endpoint = fmt.Sprintf("%s?licenseNumber=%s", endpoint, *licenseNumber)
+	}
+
+	var sr []SublocationGet
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return sr, fmt.Errorf("could not get %s sublocations from metrc: %s", status, err)
+	}
+
+	err = json.Unmarshal(responseBody, &sr)
+	if err != nil {
+		return sr, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return sr, nil
+}
+
+// GetSublocationsActive gets all active sublocations.
+// See: https://api-ca.metrc.com/Documentation/#Sublocations.get_sublocations_v1_active
+func (m *Metrc) GetSublocationsActive(licenseNumber *string) ([]SublocationGet, error) {
+	return m.getSublocationsByStatus("active", licenseNumber)
+}
+
+// GetSublocationsInactive gets all inactive sublocations.
+// See: https://api-ca.metrc.com/Documentation/#Sublocations.get_sublocations_v1_inactive
+func (m *Metrc) GetSublocationsInactive(licenseNumber *string) ([]SublocationGet, error) {
+	return m.getSublocationsByStatus("inactive", licenseNumber)
+}
+
+// PostSublocationsCreate creates new sublocations.
+// See: https://api-ca.metrc.com/Documentation/#Sublocations.post_sublocations_v1_create
+func (m *Metrc) PostSublocationsCreate(subs []SublocationPost, licenseNumber *string) ([]byte, error) {
+	endpoint := "sublocations/v1/create"
+	if licenseNumber != nil {
+		endpoint += fmt.Sprintf("?licenseNumber=%s", *licenseNumber)
+	}
+
+	body, err := json.Marshal(subs)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal sublocations: %s", err)
+	}
+
+	resp, err := m.Client.Post(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed posting sublocations: %s", err)
+	}
+	return resp, nil
+}
+
+// PutSublocationsUpdate updates existing sublocations. Note that for this endpoint, `SublocationPost.Id` is required in each `SublocationPost` in the input slice.
+// See: https://api-ca.metrc.com/Documentation/#Sublocations.put_sublocations_v1_update
+func (m *Metrc) PutSublocationsUpdate(subs []SublocationPost, licenseNumber *string) ([]byte, error) {
+	endpoint := "sublocations/v1/update"
+	if licenseNumber != nil {
+		endpoint += fmt.Sprintf("?licenseNumber=%s", *licenseNumber)
+	}
+
+	body, err := json.Marshal(subs)
+	if err != nil {
+		return []byte{}, fmt.Errorf("could not marshal sublocations: %s", err)
+	}
+
+	resp, err := m.Client.Put(endpoint, body)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed putting sublocations: %s", err)
+	}
+	return resp, nil
+}
+
+// DeleteSublocationById archives an existing sublocation by ID.
+// See: https://api-ca.metrc.com/Documentation/#Sublocations.delete_sublocations_v1_{id}
+func (m *Metrc) DeleteSublocationById(id int, licenseNumber *string) ([]byte, error) {
+	endpoint := fmt.Sprintf("sublocations/v1/%d", id)
+	if licenseNumber != nil {
+		endpoint += fmt.Sprintf("?licenseNumber=%s", *licenseNumber)
+	}
+
+	resp, err := m.Client.Delete(endpoint)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed deleting sublocation %d: %s", id, err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,43 @@
+package metrc
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSublocationsGetActive_Integration(t *testing.T) {
+	_, err := m.GetSublocationsActive(&licenseNumber)
+	assert.NoError(t, err)
+}
+
+func TestSublocationsCreateUpdateDelete_Integration(t *testing.T) {
+	// Generate a random name for a new Sublocation.
+	rand.Seed(time.Now().Unix())
+	name := fmt.Sprintf("%d", rand.Int())
+
+	_, err := m.PostSublocationsCreate([]SublocationPost{{Name: name}}, &licenseNumber)
+	assert.NoError(t, err)
+
+	// Get all active Sublocations, and then find the Id of the new Sublocation.
+	subs, err := m.GetSublocationsActive(&licenseNumber)
+	assert.NoError(t, err)
+	var subId int
+	for _, s := range subs {
+		if s.Name == name {
+			subId = s.Id
+			break
+		}
+	}
+
+	// Rename the Sublocation using update.
+	_, err = m.PutSublocationsUpdate([]SublocationPost{{Id: subId, Name: fmt.Sprintf("%d", rand.Int())}}, &licenseNumber)
+	assert.NoError(t, err)
+
+	// Delete the Sublocation using the ID.
+	_, err = m.DeleteSublocationById(subId, &licenseNumber)
+	assert.NoError(t, err)
+}
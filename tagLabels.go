@@ -0,0 +1,171 @@
+package metrc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Metrc tag labels are 24 characters: a 3 character state prefix, a 12 character facility segment, and a 9 digit sequence.
+// For example, "1A4FF0100000022000000123" has prefix "1A4", facility "FF0100000022", and sequence 123.
+const (
+	tagLabelLength    = 24
+	tagPrefixLength   = 3
+	tagFacilityLength = 12
+	tagSequenceLength = 9
+	tagSequenceMax    = 999999999
+)
+
+// tagExpandMax is the most Tags that `Next` and `Through` will expand at once, so a valid but huge range cannot exhaust memory.
+const tagExpandMax = 100000
+
+// TagKind distinguishes plant Tags from package Tags.
+type TagKind string
+
+// The kinds of Tags. The label itself does not encode its kind, so parsed labels are `TagKindUnknown`
+// unless the kind comes from Metrc's tag inventory (see `ParseTagGet`) or is set by the caller.
+const (
+	TagKindUnknown TagKind = ""
+	TagKindPlant   TagKind = "Plant"
+	TagKindPackage TagKind = "Package"
+)
+
+// Tag is a parsed Metrc tag label.
+type Tag struct {
+	Prefix   string
+	Facility string
+	Sequence int
+	Kind     TagKind
+}
+
+// ParseTag parses and validates a 24 character Metrc tag label.
+func ParseTag(label string) (Tag, error) {
+	if len(label) != tagLabelLength {
+		return Tag{}, fmt.Errorf("tag %q must be %d characters, got %d", label, tagLabelLength, len(label))
+	}
+
+	for i, r := range label {
+		isDigit := r >= '0' && r <= '9'
+		isUpper := r >= 'A' && r <= 'Z'
+		if !isDigit && !isUpper {
+			return Tag{}, fmt.Errorf("tag %q has invalid character %q at position %d", label, r, i)
+		}
+	}
+
+	prefix := label[:tagPrefixLength]
+	facility := label[tagPrefixLength : tagPrefixLength+tagFacilityLength]
+	sequence := label[tagPrefixLength+tagFacilityLength:]
+
+	seq, err := strconv.Atoi(sequence)
+	if err != nil {
+		return Tag{}, fmt.Errorf("tag %q must end in a %d digit sequence, got %q", label, tagSequenceLength, sequence)
+	}
+
+	return Tag{
+		Prefix:   prefix,
+		Facility: facility,
+		Sequence: seq,
+	}, nil
+}
+
+// ParseTagGet parses the label of a Tag from Metrc's tag inventory, taking its kind from `TagGet.TagTypeName`.
+func ParseTagGet(tg TagGet) (Tag, error) {
+	t, err := ParseTag(tg.Label)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	switch {
+	case strings.Contains(tg.TagTypeName, "Plant"):
+		t.Kind = TagKindPlant
+	case strings.Contains(tg.TagTypeName, "Package"):
+		t.Kind = TagKindPackage
+	}
+
+	return t, nil
+}
+
+// String formats the Tag back into its 24 character label.
+func (t Tag) String() string {
+	return fmt.Sprintf("%s%s%0*d", t.Prefix, t.Facility, tagSequenceLength, t.Sequence)
+}
+
+// IsPlant reports whether the Tag is known to be a plant Tag.
+func (t Tag) IsPlant() bool {
+	return t.Kind == TagKindPlant
+}
+
+// IsPackage reports whether the Tag is known to be a package Tag.
+func (t Tag) IsPackage() bool {
+	return t.Kind == TagKindPackage
+}
+
+// Next returns the n sequential Tags after t, in the same facility segment. At most `tagExpandMax` Tags are expanded at once.
+func (t Tag) Next(n int) ([]Tag, error) {
+	if n < 0 || n > tagExpandMax {
+		return []Tag{}, fmt.Errorf("cannot expand %d tags, must be between 0 and %d", n, tagExpandMax)
+	}
+	if n > tagSequenceMax-t.Sequence {
+		return []Tag{}, fmt.Errorf("tag %s has fewer than %d tags after it", t, n)
+	}
+
+	tags := make([]Tag, 0, n)
+	for i := 1; i <= n; i++ {
+		next := t
+		next.Sequence = t.Sequence + i
+		tags = append(tags, next)
+	}
+
+	return tags, nil
+}
+
+// Through returns the Tags from t to last inclusive. Both Tags must share a prefix and facility segment.
+func (t Tag) Through(last Tag) ([]Tag, error) {
+	if t.Prefix != last.Prefix || t.Facility != last.Facility {
+		return []Tag{}, fmt.Errorf("tags %s and %s are not in the same range", t, last)
+	}
+	if last.Sequence < t.Sequence {
+		return []Tag{}, fmt.Errorf("tag %s comes before %s", last, t)
+	}
+
+	next, err := t.Next(last.Sequence - t.Sequence)
+	if err != nil {
+		return []Tag{}, err
+	}
+	return append([]Tag{t}, next...), nil
+}
+
+// ValidateLabels checks that every label is a well formed Metrc tag label, returning an error naming each malformed label.
+func ValidateLabels(labels ...string) error {
+	var problems []string
+	for _, label := range labels {
+		_, err := ParseTag(label)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid tag labels: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkLabels validates labels when `Metrc.ValidateLabels` is enabled.
+func (m *Metrc) checkLabels(labels ...string) error {
+	if !m.ValidateLabels {
+		return nil
+	}
+	return ValidateLabels(labels...)
+}
+
+// checkOptionalLabels validates the non-nil labels when `Metrc.ValidateLabels` is enabled.
+func (m *Metrc) checkOptionalLabels(labels ...*string) error {
+	var ls []string
+	for _, l := range labels {
+		if l != nil {
+			ls = append(ls, *l)
+		}
+	}
+	return m.checkLabels(ls...)
+}
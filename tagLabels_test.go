@@ -0,0 +1,90 @@
+package metrc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagParse(t *testing.T) {
+	tag, err := ParseTag("1A4FF0100000022000000123")
+	assert.NoError(t, err)
+	assert.Equal(t, Tag{Prefix: "1A4", Facility: "FF0100000022", Sequence: 123}, tag)
+	assert.Equal(t, "1A4FF0100000022000000123", tag.String())
+	assert.False(t, tag.IsPlant())
+	assert.False(t, tag.IsPackage())
+
+	for _, label := range []string{
+		"",
+		"1A4FF010000002200000012",   // Too short.
+		"1A4FF01000000220000001234", // Too long.
+		"1a4FF0100000022000000123",  // Lower case.
+		"1A4FF01000000220000001X3",  // Non-numeric sequence.
+		"1A4FF0100000022-00000123",  // Punctuation.
+	} {
+		_, err := ParseTag(label)
+		assert.Error(t, err, label)
+	}
+}
+
+func TestTagParseTagGet(t *testing.T) {
+	tag, err := ParseTagGet(TagGet{Label: "1A4FF0100000022000000123", TagTypeName: "CannabisPlant"})
+	assert.NoError(t, err)
+	assert.True(t, tag.IsPlant())
+
+	tag, err = ParseTagGet(TagGet{Label: "1A4FF0100000022000000123", TagTypeName: "CannabisPackage"})
+	assert.NoError(t, err)
+	assert.True(t, tag.IsPackage())
+}
+
+func TestTagRanges(t *testing.T) {
+	tag, err := ParseTag("1A4FF0100000022000000998")
+	assert.NoError(t, err)
+
+	next, err := tag.Next(3)
+	assert.NoError(t, err)
+	assert.Equal(t, "1A4FF0100000022000000999", next[0].String())
+	assert.Equal(t, "1A4FF0100000022000001001", next[2].String())
+
+	last, err := ParseTag("1A4FF0100000022000001000")
+	assert.NoError(t, err)
+	through, err := tag.Through(last)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(through))
+
+	_, err = last.Through(tag)
+	assert.Error(t, err)
+
+	end, err := ParseTag("1A4FF0100000022999999999")
+	assert.NoError(t, err)
+	_, err = end.Next(1)
+	assert.Error(t, err)
+	_, err = tag.Next(math.MaxInt32)
+	assert.Error(t, err)
+	_, err = tag.Next(-1)
+	assert.Error(t, err)
+
+	first, err := ParseTag("1A4FF0100000022000000000")
+	assert.NoError(t, err)
+	_, err = first.Next(tagSequenceMax)
+	assert.Error(t, err)
+	next, err = first.Next(tagExpandMax)
+	assert.NoError(t, err)
+	assert.Len(t, next, tagExpandMax)
+}
+
+func TestTagValidateLabels(t *testing.T) {
+	assert.NoError(t, ValidateLabels("1A4FF0100000022000000123", "1A4FF0100000022000000124"))
+	assert.Error(t, ValidateLabels("1A4FF0100000022000000123", "bad"))
+
+	fc := makeFakeClient(map[string]string{})
+	mv := &Metrc{Client: fc, ValidateLabels: true}
+	_, err := mv.PostPackagesFinish([]PackageFinish{{Label: "bad"}}, licenseNumber)
+	assert.Error(t, err)
+	assert.Empty(t, fc.bodies)
+
+	_, err = mv.PostPackagesFinish([]PackageFinish{{Label: "1A4FF0100000022000000123"}}, licenseNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fc.bodies))
+}
@@ -0,0 +1,284 @@
+package metrc
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TagGet represents a plant or package Tag in Metrc's tag inventory.
+// See: https://testing-api-ca.metrc.com/Documentation#Tags.get_tags_v1_plant_available.GET
+type TagGet struct {
+	Id                   int     `json:"Id"`
+	Label                string  `json:"Label"`
+	TagTypeId            int     `json:"TagTypeId"`
+	TagTypeName          string  `json:"TagTypeName"`
+	TagInventoryTypeName string  `json:"TagInventoryTypeName"`
+	GroupTagTypeName     *string `json:"GroupTagTypeName"`
+	MaxGroupSize         int     `json:"MaxGroupSize"`
+	IsUsed               bool    `json:"IsUsed"`
+	IsVoided             bool    `json:"IsVoided"`
+	CommissionedDateTime string  `json:"CommissionedDateTime"`
+	UsedDateTime         *string `json:"UsedDateTime"`
+	VoidedDateTime       *string `json:"VoidedDateTime"`
+}
+
+// TagTypePlant and TagTypePackage are the tag types accepted by the tag endpoints and `MakeTagAllocator`.
+const (
+	TagTypePlant   = "plant"
+	TagTypePackage = "package"
+)
+
+// helper method to get tags of a type with the "status" endpoint
+func (m *Metrc) getTagsByStatus(tagType string, status string, licenseNumber string) ([]TagGet, error) {
+	endpoint := fmt.Sprintf("tags/v1/%s/%s", tagType, status)
+	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
+
+	var tr []TagGet
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return tr, fmt.Errorf("could not get %s %s tags response: %s", status, tagType, err)
+	}
+
+	err = json.Unmarshal(responseBody, &tr)
+	if err != nil {
+		return tr, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return tr, nil
+}
+
+// GetTagsPlantAvailable gets the plant Tags available for use.
+// See: https://testing-api-ca.metrc.com/Documentation#Tags.get_tags_v1_plant_available.GET
+func (m *Metrc) GetTagsPlantAvailable(licenseNumber string) ([]TagGet, error) {
+	return m.getTagsByStatus(TagTypePlant, "available", licenseNumber)
+}
+
+// GetTagsPackageAvailable gets the package Tags available for use.
+// See: https://testing-api-ca.metrc.com/Documentation#Tags.get_tags_v1_package_available.GET
+func (m *Metrc) GetTagsPackageAvailable(licenseNumber string) ([]TagGet, error) {
+	return m.getTagsByStatus(TagTypePackage, "available", licenseNumber)
+}
+
+// GetTagsPlantVoided gets the plant Tags that have been voided.
+// See: https://testing-api-ca.metrc.com/Documentation#Tags.get_tags_v1_plant_voided.GET
+func (m *Metrc) GetTagsPlantVoided(licenseNumber string) ([]TagGet, error) {
+	return m.getTagsByStatus(TagTypePlant, "voided", licenseNumber)
+}
+
+// GetTagsPackageVoided gets the package Tags that have been voided.
+// See: https://testing-api-ca.metrc.com/Documentation#Tags.get_tags_v1_package_voided.GET
+func (m *Metrc) GetTagsPackageVoided(licenseNumber string) ([]TagGet, error) {
+	return m.getTagsByStatus(TagTypePackage, "voided", licenseNumber)
+}
+
+// GetTagsTypes gets the types of Tags.
+// See: https://testing-api-ca.metrc.com/Documentation#Tags.get_tags_v1_types.GET
+func (m *Metrc) GetTagsTypes() ([]string, error) {
+	endpoint := "tags/v1/types"
+
+	var types []string
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return types, fmt.Errorf("could not get tag types response: %s", err)
+	}
+
+	err = json.Unmarshal(responseBody, &types)
+	if err != nil {
+		return types, fmt.Errorf("could not unmarshal response: %s", err)
+	}
+
+	return types, nil
+}
+
+// TagAllocator hands out available Tags of one type to workflows, so concurrent callers never receive the same Tag.
+// Reservations are local to the allocator; share one allocator between every operator of a license.
+// Reservations that are neither used nor released within the allocator's TTL are returned to the pool.
+type TagAllocator struct {
+	Metrc         *Metrc
+	TagType       string
+	LicenseNumber string
+	TTL           time.Duration
+
+	mu       sync.Mutex
+	reserved map[string]*TagReservation
+	now      func() time.Time
+
+	// used maps each used Tag to the generation it was used in, until Metrc stops listing it as available.
+	// generation counts uses and prunes, and pruned is the generation of the last prune: a fetch started
+	// before it may list Tags that are no longer in used, so it is retried.
+	used       map[string]int
+	generation int
+	pruned     int
+}
+
+// TagReservation is a set of Tags reserved for a single workflow.
+type TagReservation struct {
+	Labels []string
+
+	allocator *TagAllocator
+	expires   time.Time
+	pending   map[string]bool
+}
+
+// tagFetchAttempts is how many times `Reserve` fetches available Tags before giving up on a pool that keeps changing.
+const tagFetchAttempts = 3
+
+// MakeTagAllocator creates an allocator for tagType (`TagTypePlant` or `TagTypePackage`) at a license.
+// A zero ttl keeps reservations until they are used or released.
+func MakeTagAllocator(m *Metrc, tagType string, licenseNumber string, ttl time.Duration) *TagAllocator {
+	return &TagAllocator{
+		Metrc:         m,
+		TagType:       tagType,
+		LicenseNumber: licenseNumber,
+		TTL:           ttl,
+		reserved:      map[string]*TagReservation{},
+		used:          map[string]int{},
+		now:           time.Now,
+	}
+}
+
+// Reserve reserves the next n available Tags, in label order.
+// Available Tags are fetched from Metrc on every call, without holding up other callers of the allocator,
+// and Tags reserved or used through this allocator are skipped.
+func (a *TagAllocator) Reserve(n int) (*TagReservation, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("cannot reserve %d tags", n)
+	}
+	if a.TagType != TagTypePlant && a.TagType != TagTypePackage {
+		return nil, fmt.Errorf("unknown tag type %s", a.TagType)
+	}
+
+	for attempt := 0; attempt < tagFetchAttempts; attempt++ {
+		a.mu.Lock()
+		start := a.generation
+		a.mu.Unlock()
+
+		available, err := a.Metrc.getTagsByStatus(a.TagType, "available", a.LicenseNumber)
+		if err != nil {
+			return nil, fmt.Errorf("could not get available tags: %s", err)
+		}
+
+		a.mu.Lock()
+		if a.pruned > start {
+			a.mu.Unlock()
+			continue
+		}
+		r, err := a.reserveLocked(n, available, start)
+		a.mu.Unlock()
+		return r, err
+	}
+
+	return nil, fmt.Errorf("available %s tags kept changing while they were fetched", a.TagType)
+}
+
+// reserveLocked reserves n Tags from the available Tags of a fetch started at generation start.
+// Must be called with a.mu held.
+func (a *TagAllocator) reserveLocked(n int, available []TagGet, start int) (*TagReservation, error) {
+	a.releaseExpiredLocked()
+	a.pruneUsedLocked(available, start)
+
+	labels := make([]string, 0, len(available))
+	for _, tag := range available {
+		if tag.IsUsed || tag.IsVoided || a.reserved[tag.Label] != nil {
+			continue
+		}
+		if _, ok := a.used[tag.Label]; ok {
+			continue
+		}
+		labels = append(labels, tag.Label)
+	}
+	sort.Strings(labels)
+
+	if len(labels) < n {
+		return nil, fmt.Errorf("wanted %d %s tags but only %d are available", n, a.TagType, len(labels))
+	}
+
+	r := &TagReservation{
+		Labels:    labels[:n:n],
+		allocator: a,
+		pending:   map[string]bool{},
+	}
+	if a.TTL > 0 {
+		r.expires = a.now().Add(a.TTL)
+	}
+	for _, label := range r.Labels {
+		r.pending[label] = true
+		a.reserved[label] = r
+	}
+
+	return r, nil
+}
+
+// pruneUsedLocked forgets used Tags that were used before a fetch started at generation start and that it no longer lists.
+// Must be called with a.mu held.
+func (a *TagAllocator) pruneUsedLocked(available []TagGet, start int) {
+	listed := map[string]bool{}
+	for _, tag := range available {
+		listed[tag.Label] = true
+	}
+
+	pruned := false
+	for label, used := range a.used {
+		if used <= start && !listed[label] {
+			delete(a.used, label)
+			pruned = true
+		}
+	}
+	if pruned {
+		a.generation++
+		a.pruned = a.generation
+	}
+}
+
+// ReleaseExpired returns the unused Tags of expired reservations to the pool.
+func (a *TagAllocator) ReleaseExpired() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.releaseExpiredLocked()
+}
+
+// releaseExpiredLocked must be called with a.mu held.
+func (a *TagAllocator) releaseExpiredLocked() {
+	now := a.now()
+	for label, r := range a.reserved {
+		if !r.expires.IsZero() && now.After(r.expires) {
+			delete(a.reserved, label)
+			delete(r.pending, label)
+		}
+	}
+}
+
+// Use marks label as consumed, e.g. after a successful `PostPackagesCreate`, so it is never handed out again.
+func (r *TagReservation) Use(label string) error {
+	a := r.allocator
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !r.pending[label] || a.reserved[label] != r {
+		return fmt.Errorf("tag %s is not held by this reservation", label)
+	}
+
+	delete(r.pending, label)
+	delete(a.reserved, label)
+	a.generation++
+	a.used[label] = a.generation
+	return nil
+}
+
+// Release returns every Tag in the reservation that has not been used to the pool.
+func (r *TagReservation) Release() {
+	a := r.allocator
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for label := range r.pending {
+		if a.reserved[label] == r {
+			delete(a.reserved, label)
+		}
+		delete(r.pending, label)
+	}
+}
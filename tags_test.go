@@ -0,0 +1,88 @@
+package metrc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagsPlantAvailable_Integration(t *testing.T) {
+	_, err := m.GetTagsPlantAvailable(licenseNumber)
+	assert.NoError(t, err)
+}
+
+func TestTagsPackageAvailable_Integration(t *testing.T) {
+	_, err := m.GetTagsPackageAvailable(licenseNumber)
+	assert.NoError(t, err)
+}
+
+func TestTagsTypes_Integration(t *testing.T) {
+	_, err := m.GetTagsTypes()
+	assert.NoError(t, err)
+}
+
+func TestTagAllocator(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"tags/v1/package/available": `[
+			{"Label": "1A4FF0100000022000000004"},
+			{"Label": "1A4FF0100000022000000002"},
+			{"Label": "1A4FF0100000022000000003"},
+			{"Label": "1A4FF0100000022000000001"}
+		]`,
+	})
+	a := MakeTagAllocator(&Metrc{Client: fc}, TagTypePackage, licenseNumber, time.Hour)
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+
+	// Concurrent reservations never overlap.
+	var wg sync.WaitGroup
+	reservations := make([]*TagReservation, 2)
+	for i := range reservations {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r, err := a.Reserve(2)
+			assert.NoError(t, err)
+			reservations[i] = r
+		}(i)
+	}
+	wg.Wait()
+	got := append(append([]string{}, reservations[0].Labels...), reservations[1].Labels...)
+	assert.ElementsMatch(t, []string{
+		"1A4FF0100000022000000001",
+		"1A4FF0100000022000000002",
+		"1A4FF0100000022000000003",
+		"1A4FF0100000022000000004",
+	}, got)
+
+	_, err := a.Reserve(1)
+	assert.Error(t, err)
+
+	// Used tags stay out of the pool, released tags come back.
+	first := reservations[0]
+	assert.NoError(t, first.Use(first.Labels[0]))
+	assert.Error(t, first.Use(reservations[1].Labels[0]))
+	first.Release()
+
+	r, err := a.Reserve(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{first.Labels[1]}, r.Labels)
+
+	// Expired reservations are released on the next reservation.
+	now = now.Add(2 * time.Hour)
+	r, err = a.Reserve(3)
+	assert.NoError(t, err)
+	assert.NotContains(t, r.Labels, first.Labels[0])
+
+	// Used tags are forgotten once Metrc stops listing them as available.
+	assert.Contains(t, a.used, first.Labels[0])
+	fc.mu.Lock()
+	fc.responses["tags/v1/package/available"] = `[{"Label": "1A4FF0100000022000000004"}]`
+	fc.mu.Unlock()
+	now = now.Add(2 * time.Hour)
+	_, err = a.Reserve(1)
+	assert.NoError(t, err)
+	assert.NotContains(t, a.used, first.Labels[0])
+}
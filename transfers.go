@@ -58,10 +58,10 @@ func (m *Metrc) getTransfersByStatus(status string, licenseNumber string, lastMo
 	endpoint := fmt.Sprintf("transfers/v1/%s", status)
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 	if lastModifiedStart != nil {
-		endpoint += fmt.Sprintf("?lastModifiedStart=%s", *lastModifiedStart)
+		endpoint += fmt.Sprintf("&lastModifiedStart=%s", *lastModifiedStart)
 	}
 	if lastModifiedEnd != nil {
-		endpoint += fmt.Sprintf("?lastModifiedEnd=%s", *lastModifiedEnd)
+		endpoint += fmt.Sprintf("&lastModifiedEnd=%s", *lastModifiedEnd)
 	}
 
 	var tr []Transfer
@@ -474,10 +474,10 @@ func (m *Metrc) GetTransfersTemplates(licenseNumber string, lastModifiedStart *s
 	endpoint := "transfers/v1/templates"
 	endpoint += fmt.Sprintf("?licenseNumber=%s", licenseNumber)
 	if lastModifiedStart != nil {
-		endpoint += fmt.Sprintf("?lastModifiedStart=%s", *lastModifiedStart)
+		endpoint += fmt.Sprintf("&lastModifiedStart=%s", *lastModifiedStart)
 	}
 	if lastModifiedEnd != nil {
-		endpoint += fmt.Sprintf("?lastModifiedEnd=%s", *lastModifiedEnd)
+		endpoint += fmt.Sprintf("&lastModifiedEnd=%s", *lastModifiedEnd)
 	}
 
 	var tr []Transfer
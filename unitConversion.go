@@ -0,0 +1,202 @@
+package metrc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The quantity types of `UnitsOfMeasure`. Quantities convert only between units of the same type.
+const (
+	QuantityTypeCount  = "CountBased"
+	QuantityTypeWeight = "WeightBased"
+	QuantityTypeVolume = "VolumeBased"
+)
+
+// Unit is a unit of measure known to the converter.
+// Factor is the size of the unit in the base unit of its type: grams, milliliters, or each.
+type Unit struct {
+	Name         string
+	Abbreviation string
+	QuantityType string
+	Factor       float64
+}
+
+// units are the units the converter supports, named as Metrc names them.
+var units = []Unit{
+	{"Each", "ea", QuantityTypeCount, 1},
+	{"Grams", "g", QuantityTypeWeight, 1},
+	{"Milligrams", "mg", QuantityTypeWeight, 0.001},
+	{"Kilograms", "kg", QuantityTypeWeight, 1000},
+	{"Ounces", "oz", QuantityTypeWeight, 28.349523125},
+	{"Pounds", "lb", QuantityTypeWeight, 453.59237},
+	{"Milliliters", "ml", QuantityTypeVolume, 1},
+	{"Liters", "l", QuantityTypeVolume, 1000},
+	{"Fluid Ounces", "fl oz", QuantityTypeVolume, 29.5735295625},
+}
+
+// unitAliases are spellings seen in Metrc responses and user input, besides each unit's name and abbreviation.
+var unitAliases = map[string]string{
+	"gram":        "Grams",
+	"gm":          "Grams",
+	"milligram":   "Milligrams",
+	"kilogram":    "Kilograms",
+	"ounce":       "Ounces",
+	"pound":       "Pounds",
+	"lbs":         "Pounds",
+	"milliliter":  "Milliliters",
+	"millilitre":  "Milliliters",
+	"millilitres": "Milliliters",
+	"liter":       "Liters",
+	"litre":       "Liters",
+	"litres":      "Liters",
+	"fluid ounce": "Fluid Ounces",
+	"floz":        "Fluid Ounces",
+	"fl. oz.":     "Fluid Ounces",
+	"fl. oz":      "Fluid Ounces",
+	"each":        "Each",
+}
+
+var unitsByKey = makeUnitsByKey()
+
+func makeUnitsByKey() map[string]Unit {
+	byKey := map[string]Unit{}
+	byName := map[string]Unit{}
+	for _, u := range units {
+		byName[u.Name] = u
+		byKey[strings.ToLower(u.Name)] = u
+		byKey[strings.ToLower(u.Abbreviation)] = u
+	}
+	for alias, name := range unitAliases {
+		byKey[alias] = byName[name]
+	}
+	return byKey
+}
+
+// NormalizeUnit resolves a Metrc unit name, abbreviation, or common spelling, case insensitively, e.g. "g", "Grams", and "gram" are all `Grams`.
+func NormalizeUnit(unit string) (Unit, error) {
+	key := strings.Join(strings.Fields(strings.ToLower(unit)), " ")
+	u, ok := unitsByKey[key]
+	if !ok {
+		return Unit{}, fmt.Errorf("unknown unit of measure %q", unit)
+	}
+	return u, nil
+}
+
+// SameUnit reports whether two names or abbreviations refer to the same unit, e.g. `UnitOfMeasureName` and `UnitOfMeasureAbbreviation`.
+func SameUnit(a string, b string) bool {
+	ua, err := NormalizeUnit(a)
+	if err != nil {
+		return false
+	}
+	ub, err := NormalizeUnit(b)
+	if err != nil {
+		return false
+	}
+	return ua.Name == ub.Name
+}
+
+// ConvertQuantity converts a quantity between two units of the same quantity type. Converting between types, e.g. grams to each, is an error.
+func ConvertQuantity(quantity float64, from string, to string) (float64, error) {
+	f, err := NormalizeUnit(from)
+	if err != nil {
+		return 0, err
+	}
+	t, err := NormalizeUnit(to)
+	if err != nil {
+		return 0, err
+	}
+	if f.QuantityType != t.QuantityType {
+		return 0, fmt.Errorf("cannot convert %s (%s) to %s (%s)", f.Name, f.QuantityType, t.Name, t.QuantityType)
+	}
+	if f.Name == t.Name {
+		return quantity, nil
+	}
+
+	return quantity * f.Factor / t.Factor, nil
+}
+
+// Unit resolves a unit of measure from Metrc to the converter's `Unit`.
+func (u UnitsOfMeasure) Unit() (Unit, error) {
+	unit, err := NormalizeUnit(u.Name)
+	if err != nil {
+		unit, err = NormalizeUnit(u.Abbreviation)
+	}
+	return unit, err
+}
+
+// ConvertTo returns the Ingredient with its quantity in another unit.
+func (i Ingredient) ConvertTo(unit string) (Ingredient, error) {
+	q, t, err := convertTo(i.Quantity, i.UnitOfMeasure, unit)
+	if err != nil {
+		return i, fmt.Errorf("could not convert ingredient %s: %s", i.Package, err)
+	}
+	i.Quantity, i.UnitOfMeasure = q, t
+	return i, nil
+}
+
+// ConvertTo returns the adjustment with its quantity in another unit.
+func (a PackageAdjust) ConvertTo(unit string) (PackageAdjust, error) {
+	q, t, err := convertTo(a.Quantity, a.UnitOfMeasure, unit)
+	if err != nil {
+		return a, fmt.Errorf("could not convert adjustment of %s: %s", a.Label, err)
+	}
+	a.Quantity, a.UnitOfMeasure = q, t
+	return a, nil
+}
+
+// ConvertTo returns the transaction with its quantity in another unit. The total amount is unchanged.
+func (s SalesTransactionPost) ConvertTo(unit string) (SalesTransactionPost, error) {
+	q, t, err := convertTo(s.Quantity, s.UnitsOfMeasure, unit)
+	if err != nil {
+		return s, fmt.Errorf("could not convert transaction of %s: %s", s.PackageLabel, err)
+	}
+	s.Quantity, s.UnitsOfMeasure = q, t
+	return s, nil
+}
+
+// ConvertTo returns the harvest ingredient with its weight in another unit.
+func (h HarvestIngredient) ConvertTo(unit string) (HarvestIngredient, error) {
+	w, t, err := convertTo(h.Weight, h.UnitOfWeight, unit)
+	if err != nil {
+		return h, fmt.Errorf("could not convert harvest ingredient: %s", err)
+	}
+	h.Weight, h.UnitOfWeight = w, t
+	return h, nil
+}
+
+// ConvertTo returns the harvest package with its unit of weight, and the weight of every ingredient, in another unit.
+func (p HarvestPackagePost) ConvertTo(unit string) (HarvestPackagePost, error) {
+	u, err := NormalizeUnit(unit)
+	if err != nil {
+		return p, err
+	}
+	if u.QuantityType != QuantityTypeWeight {
+		return p, fmt.Errorf("harvest packages must be in a weight unit, got %s", u.Name)
+	}
+
+	ingredients := make([]HarvestIngredient, len(p.Ingredients))
+	for i, h := range p.Ingredients {
+		ingredients[i], err = h.ConvertTo(u.Name)
+		if err != nil {
+			return p, fmt.Errorf("could not convert harvest package %s: %s", p.Tag, err)
+		}
+	}
+
+	p.Ingredients = ingredients
+	p.UnitOfWeight = u.Name
+	return p, nil
+}
+
+// convertTo converts a quantity and returns it with the Metrc name of the target unit.
+func convertTo(quantity float64, from string, to string) (float64, string, error) {
+	q, err := ConvertQuantity(quantity, from, to)
+	if err != nil {
+		return quantity, from, err
+	}
+
+	u, err := NormalizeUnit(to)
+	if err != nil {
+		return quantity, from, err
+	}
+	return q, u.Name, nil
+}
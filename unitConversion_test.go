@@ -0,0 +1,76 @@
+package metrc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitConversion(t *testing.T) {
+	for _, s := range []string{"g", "Grams", "gram", " GRAMS "} {
+		u, err := NormalizeUnit(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, "Grams", u.Name, s)
+	}
+	u, err := NormalizeUnit("fl  oz")
+	assert.NoError(t, err)
+	assert.Equal(t, "Fluid Ounces", u.Name)
+	_, err = NormalizeUnit("cubits")
+	assert.Error(t, err)
+	assert.True(t, SameUnit("Milliliters", "ml"))
+	assert.False(t, SameUnit("Milliliters", "l"))
+
+	cases := []struct {
+		quantity float64
+		from, to string
+		want     float64
+	}{
+		{1, "kg", "g", 1000},
+		{1500, "Milligrams", "Grams", 1.5},
+		{1, "lb", "oz", 16},
+		{1, "Ounces", "Grams", 28.349523125},
+		{2, "Liters", "Milliliters", 2000},
+		{1, "fl oz", "ml", 29.5735295625},
+		{3, "Each", "ea", 3},
+	}
+	for _, c := range cases {
+		got, err := ConvertQuantity(c.quantity, c.from, c.to)
+		assert.NoError(t, err)
+		assert.InDelta(t, c.want, got, 1e-9, "%g %s to %s", c.quantity, c.from, c.to)
+	}
+
+	_, err = ConvertQuantity(1, "Grams", "Each")
+	assert.Error(t, err)
+	_, err = ConvertQuantity(1, "Grams", "Milliliters")
+	assert.Error(t, err)
+
+	uom, err := UnitsOfMeasure{QuantityType: "WeightBased", Name: "Pounds", Abbreviation: "lb"}.Unit()
+	assert.NoError(t, err)
+	assert.Equal(t, QuantityTypeWeight, uom.QuantityType)
+}
+
+func TestUnitConversionPayloads(t *testing.T) {
+	i, err := Ingredient{Package: "A", Quantity: 0.5, UnitOfMeasure: "kg"}.ConvertTo("g")
+	assert.NoError(t, err)
+	assert.Equal(t, Ingredient{Package: "A", Quantity: 500, UnitOfMeasure: "Grams"}, i)
+
+	s, err := SalesTransactionPost{PackageLabel: "A", Quantity: 1, UnitsOfMeasure: "Ounces", TotalAmount: 30}.ConvertTo("Grams")
+	assert.NoError(t, err)
+	assert.InDelta(t, 28.349523125, s.Quantity, 1e-9)
+	assert.Equal(t, 30.0, s.TotalAmount)
+
+	_, err = PackageAdjust{Label: "A", Quantity: 1, UnitOfMeasure: "Each"}.ConvertTo("Grams")
+	assert.Error(t, err)
+
+	name := "Harvest A"
+	p, err := HarvestPackagePost{
+		Tag:          "A",
+		UnitOfWeight: "Grams",
+		Ingredients:  []HarvestIngredient{{HarvestName: &name, Weight: 2, UnitOfWeight: "Pounds"}},
+	}.ConvertTo("kg")
+	assert.NoError(t, err)
+	assert.Equal(t, "Kilograms", p.UnitOfWeight)
+	assert.InDelta(t, 0.90718474, p.Ingredients[0].Weight, 1e-9)
+	_, err = HarvestPackagePost{}.ConvertTo("Liters")
+	assert.Error(t, err)
+}
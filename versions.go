@@ -0,0 +1,148 @@
+package metrc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// APIVersion is a version of the Metrc API, which appears in every endpoint path, e.g. "packages/v1/active".
+// Only the paged list endpoints described on `Metrc.ListVersion` can be moved to v2; a full v2 migration with mapped
+// payloads for every endpoint is not supported.
+type APIVersion string
+
+// The Metrc API versions supported by this package.
+const (
+	APIVersionV1 APIVersion = "v1"
+	APIVersionV2 APIVersion = "v2"
+)
+
+// v2PageSize is the largest page size accepted by v2 list endpoints.
+const v2PageSize = 20
+
+// v2Page is the envelope wrapped around every v2 list response.
+// See: https://api-ca.metrc.com/Documentation/#getting-started_paging
+type v2Page struct {
+	Data       json.RawMessage `json:"Data"`
+	Total      int             `json:"Total"`
+	PageSize   int             `json:"PageSize"`
+	Page       int             `json:"Page"`
+	TotalPages int             `json:"TotalPages"`
+}
+
+// listVersionFor returns the API version to use for a license.
+// A license listed in `Metrc.LicenseListVersions` uses that version, otherwise the state wide `Metrc.ListVersion` applies, defaulting to v1.
+func (m *Metrc) listVersionFor(licenseNumber string) APIVersion {
+	if v, ok := m.LicenseListVersions[licenseNumber]; ok && v != "" {
+		return v
+	}
+	if m.ListVersion != "" {
+		return m.ListVersion
+	}
+	return APIVersionV1
+}
+
+// versionedEndpoint builds the endpoint for a resource and path at the version used by the license.
+// For example, ("packages", "active") becomes "packages/v1/active?licenseNumber=..." for a v1 license.
+func (m *Metrc) versionedEndpoint(resource string, path string, licenseNumber string, params url.Values) string {
+	endpoint := fmt.Sprintf("%s/%s", resource, m.listVersionFor(licenseNumber))
+	if path != "" {
+		endpoint += "/" + path
+	}
+
+	q := url.Values{}
+	for k, vs := range params {
+		q[k] = vs
+	}
+	q.Set("licenseNumber", licenseNumber)
+
+	return endpoint + "?" + q.Encode()
+}
+
+// lastModifiedParams returns the optional last modified range accepted by list endpoints.
+func lastModifiedParams(lastModifiedStart *string, lastModifiedEnd *string) url.Values {
+	params := url.Values{}
+	if lastModifiedStart != nil {
+		params.Set("lastModifiedStart", *lastModifiedStart)
+	}
+	if lastModifiedEnd != nil {
+		params.Set("lastModifiedEnd", *lastModifiedEnd)
+	}
+	return params
+}
+
+// getList gets a list endpoint at the version used by the license and unmarshals every item into out, which must point to a slice.
+// v1 returns the whole list in one response. v2 returns pages wrapped in an envelope, so every page is fetched
+// and the items are combined, and callers get the same types from either version.
+func (m *Metrc) getList(resource string, path string, licenseNumber string, params url.Values, out interface{}) error {
+	if m.listVersionFor(licenseNumber) == APIVersionV1 {
+		endpoint := m.versionedEndpoint(resource, path, licenseNumber, params)
+		responseBody, err := m.Client.Get(endpoint)
+		if err != nil {
+			return fmt.Errorf("could not get %s: %s", endpoint, err)
+		}
+
+		err = json.Unmarshal(responseBody, out)
+		if err != nil {
+			return fmt.Errorf("could not unmarshal response: %s", err)
+		}
+		return nil
+	}
+
+	var items []json.RawMessage
+	for pageNumber := 1; ; pageNumber++ {
+		page, err := m.getPage(resource, path, licenseNumber, params, pageNumber, v2PageSize)
+		if err != nil {
+			return err
+		}
+
+		var pageItems []json.RawMessage
+		err = json.Unmarshal(page.Data, &pageItems)
+		if err != nil {
+			return fmt.Errorf("could not unmarshal page %d data: %s", pageNumber, err)
+		}
+		items = append(items, pageItems...)
+
+		if pageNumber >= page.TotalPages || len(pageItems) == 0 {
+			break
+		}
+	}
+
+	// Re-encode the combined items so they unmarshal exactly as a v1 response would.
+	combined, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("could not combine pages: %s", err)
+	}
+	if items == nil {
+		combined = []byte("[]")
+	}
+
+	err = json.Unmarshal(combined, out)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal response: %s", err)
+	}
+	return nil
+}
+
+// getPage gets one page of a v2 list endpoint.
+func (m *Metrc) getPage(resource string, path string, licenseNumber string, params url.Values, pageNumber int, pageSize int) (v2Page, error) {
+	q := url.Values{}
+	for k, vs := range params {
+		q[k] = vs
+	}
+	q.Set("pageNumber", fmt.Sprint(pageNumber))
+	q.Set("pageSize", fmt.Sprint(pageSize))
+	endpoint := m.versionedEndpoint(resource, path, licenseNumber, q)
+
+	var page v2Page
+	responseBody, err := m.Client.Get(endpoint)
+	if err != nil {
+		return page, fmt.Errorf("could not get %s: %s", endpoint, err)
+	}
+
+	err = json.Unmarshal(responseBody, &page)
+	if err != nil {
+		return page, fmt.Errorf("could not unmarshal page %d: %s", pageNumber, err)
+	}
+	return page, nil
+}
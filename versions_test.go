@@ -0,0 +1,67 @@
+package metrc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionsEndpoint(t *testing.T) {
+	m := &Metrc{
+		ListVersion:         APIVersionV1,
+		LicenseListVersions: map[string]APIVersion{"LIC-2": APIVersionV2},
+	}
+
+	assert.Equal(t, "packages/v1/active?licenseNumber=LIC-1", m.versionedEndpoint("packages", "active", "LIC-1", nil))
+	assert.Equal(t, "packages/v2/active?licenseNumber=LIC-2", m.versionedEndpoint("packages", "active", "LIC-2", nil))
+
+	m.ListVersion = APIVersionV2
+	assert.Equal(t, APIVersionV2, m.listVersionFor("LIC-1"))
+	assert.Equal(t, APIVersionV1, (&Metrc{}).listVersionFor("LIC-1"))
+}
+
+func TestVersionsGetList(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"packages/v1/active": `[{"Id": 1}, {"Id": 2}, {"Id": 3}]`,
+		"packages/v2/active?licenseNumber=LIC-2&pageNumber=1&pageSize=20": `{"Data": [{"Id": 1}, {"Id": 2}], "Page": 1, "TotalPages": 2}`,
+		"packages/v2/active?licenseNumber=LIC-2&pageNumber=2&pageSize=20": `{"Data": [{"Id": 3}], "Page": 2, "TotalPages": 2}`,
+		"plants/v2/vegetative": `{"Data": [], "Page": 1, "TotalPages": 0}`,
+	})
+	m := &Metrc{
+		Client:              fc,
+		LicenseListVersions: map[string]APIVersion{"LIC-2": APIVersionV2},
+	}
+
+	v1, err := m.GetPackagesActive("LIC-1", nil, nil)
+	assert.NoError(t, err)
+	v2, err := m.GetPackagesActive("LIC-2", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, v1, v2)
+	assert.Len(t, v2, 3)
+
+	plants, err := m.GetPlantsVegetative("LIC-2", nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, plants)
+}
+
+func TestVersionsGetSalesReceipts(t *testing.T) {
+	fc := makeFakeClient(map[string]string{
+		"sales/v1/receipts/active?licenseNumber=LIC-1&salesDateEnd=2026-01-31&salesDateStart=2026-01-01":                          `[{"Id": 1}]`,
+		"sales/v2/receipts/active?licenseNumber=LIC-2&pageNumber=1&pageSize=20&salesDateEnd=2026-01-31&salesDateStart=2026-01-01": `{"Data": [{"Id": 1}], "Page": 1, "TotalPages": 1}`,
+	})
+	m := &Metrc{
+		Client:              fc,
+		LicenseListVersions: map[string]APIVersion{"LIC-2": APIVersionV2},
+	}
+	start, end := "2026-01-01", "2026-01-31"
+
+	v1, err := m.GetSalesReceiptsActive("LIC-1", &start, &end, nil, nil)
+	assert.NoError(t, err)
+	v2, err := m.GetSalesReceiptsActive("LIC-2", &start, &end, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, v1, v2)
+	assert.Len(t, v2, 1)
+
+	_, err = m.GetSalesReceiptsInactive("LIC-1", &start, nil, &start, nil)
+	assert.Error(t, err)
+}